@@ -2,6 +2,7 @@ package cached
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net/http"
 )
@@ -18,6 +19,9 @@ func URLFetcher(client *http.Client) Function[string, []byte] {
 			return nil, err
 		}
 		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: %s", u, resp.Status)
+		}
 
 		b, err := io.ReadAll(resp.Body)
 		if err != nil {
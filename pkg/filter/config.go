@@ -9,6 +9,9 @@ import (
 
 type Config struct {
 	AnyOf []string `yaml:"anyOf"`
+	// Versions constrains the versions of packages by name, see MatchesVersions. Only the debian
+	// ecosystem applies it, npm rejects it.
+	Versions map[string]string `yaml:"versions"`
 }
 
 func (c Config) PolicyNames() []string {
@@ -24,13 +27,32 @@ func CueConfigToPredicate[T any](root string, cfg Config) (Predicate[T], error)
 		}
 		anyOf = append(anyOf, pred)
 	}
-	pred := AnyOf(anyOf...)
+	return jsonPredicate[T](AnyOf(anyOf...)), nil
+}
+
+// CuePoliciesToPredicate is CueConfigToPredicate for policies that have already been read, keyed by name.
+func CuePoliciesToPredicate[T any](policies map[string]string, cfg Config) (Predicate[T], error) {
+	var anyOf []Predicate[[]byte]
+	for _, s := range cfg.AnyOf {
+		src, ok := policies[s]
+		if !ok {
+			return nil, fmt.Errorf("policy %q not found", s)
+		}
+		pred, err := MatchesCueSource(s, src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cue %q: %w", s, err)
+		}
+		anyOf = append(anyOf, pred)
+	}
+	return jsonPredicate[T](AnyOf(anyOf...)), nil
+}
 
+func jsonPredicate[T any](pred Predicate[[]byte]) Predicate[T] {
 	return func(ctx context.Context, t T) (bool, error) {
 		b, err := json.Marshal(t)
 		if err != nil {
 			return false, fmt.Errorf("json error: %w", err)
 		}
 		return pred(ctx, b)
-	}, nil
+	}
 }
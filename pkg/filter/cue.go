@@ -3,6 +3,7 @@ package filter
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"cuelang.org/go/cue"
 	"cuelang.org/go/cue/cuecontext"
@@ -21,25 +22,48 @@ func MatchesCue(entrypoints ...string) (Predicate[[]byte], error) {
 		}
 
 		val := ctx.BuildInstance(i)
-		if err := val.Err(); err != nil {
+		if err := checkCueValue(val, i.BuildFiles[0].Filename); err != nil {
 			return nil, err
 		}
 		values = append(values, val)
+	}
+	return cuePredicate(values)
+}
 
-		// Don't allow policies without any constraints, they unintentionally allow everything
-		if s, err := val.Struct(); err != nil {
-			return nil, err
-		} else if s.Len() == 0 {
-			return nil, fmt.Errorf("no constraints found in %s", i.BuildFiles[0].Filename)
-		}
+// MatchesCueSource is MatchesCue for a policy that has already been read into memory.
+func MatchesCueSource(filename, src string) (Predicate[[]byte], error) {
+	val := cuecontext.New().CompileString(src, cue.Filename(filename))
+	if err := checkCueValue(val, filename); err != nil {
+		return nil, err
+	}
+	return cuePredicate([]cue.Value{val})
+}
+
+func checkCueValue(val cue.Value, filename string) error {
+	if err := val.Err(); err != nil {
+		return err
 	}
 
+	// Don't allow policies without any constraints, they unintentionally allow everything
+	if s, err := val.Struct(); err != nil {
+		return err
+	} else if s.Len() == 0 {
+		return fmt.Errorf("no constraints found in %s", filename)
+	}
+	return nil
+}
+
+func cuePredicate(values []cue.Value) (Predicate[[]byte], error) {
 	// Don't allow predicates without policies, they unintentionally allow everything
 	if len(values) == 0 {
 		return nil, fmt.Errorf("no values loaded")
 	}
 
+	// Values share a cue.Context, which is not safe for concurrent use:
+	var mu sync.Mutex
 	return func(ctx context.Context, b []byte) (bool, error) {
+		mu.Lock()
+		defer mu.Unlock()
 		for _, val := range values {
 			err := val.Unify(val.Context().CompileBytes(b)).Err()
 			if err != nil {
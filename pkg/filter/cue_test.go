@@ -3,6 +3,7 @@ package filter_test
 import (
 	"context"
 	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -85,3 +86,46 @@ func TestMatchesCue_ParseEarly(t *testing.T) {
 	_, err = filter.MatchesCue("testdata/empty.cue")
 	assert.Error(t, err)
 }
+
+func TestMatchesCueSource(t *testing.T) {
+	ctx := context.Background()
+	src, err := os.ReadFile("testdata/tags.cue")
+	require.NoError(t, err)
+
+	pred, err := filter.MatchesCueSource("tags.cue", string(src))
+	require.NoError(t, err)
+
+	b, err := json.Marshal(TestPackage{Name: "test", Tags: []string{"tag2", "tag4"}})
+	require.NoError(t, err)
+	ok, err := pred(ctx, b)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	b, err = json.Marshal(TestPackage{Name: "test", Tags: []string{"tag1"}})
+	require.NoError(t, err)
+	ok, err = pred(ctx, b)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = filter.MatchesCueSource("empty.cue", "")
+	assert.Error(t, err)
+}
+
+func TestCuePoliciesToPredicate(t *testing.T) {
+	ctx := context.Background()
+	policies := map[string]string{
+		"foo.cue": `name: "foo"`,
+		"bar.cue": `name: "bar"`,
+	}
+
+	pred, err := filter.CuePoliciesToPredicate[TestPackage](policies, filter.Config{AnyOf: []string{"foo.cue", "bar.cue"}})
+	require.NoError(t, err)
+	for name, expected := range map[string]bool{"foo": true, "bar": true, "baz": false} {
+		ok, err := pred(ctx, TestPackage{Name: name})
+		require.NoError(t, err)
+		assert.Equal(t, expected, ok, name)
+	}
+
+	_, err = filter.CuePoliciesToPredicate[TestPackage](policies, filter.Config{AnyOf: []string{"missing.cue"}})
+	assert.Error(t, err)
+}
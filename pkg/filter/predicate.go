@@ -2,7 +2,6 @@ package filter
 
 import (
 	"context"
-	"fmt"
 )
 
 type Predicate[T any] func(context.Context, T) (bool, error)
@@ -22,12 +21,24 @@ func AnyOf[T any](preds ...Predicate[T]) Predicate[T] {
 	}
 }
 
+func AllOf[T any](preds ...Predicate[T]) Predicate[T] {
+	return func(ctx context.Context, t T) (bool, error) {
+		for _, pred := range preds {
+			ok, err := pred(ctx, t)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
 func FilterSlice[T any](ctx context.Context, pred Predicate[T], in ...T) ([]T, error) {
 	var result []T
-	for i, t := range in {
-		if i%100 == 0 {
-			fmt.Println(i)
-		}
+	for _, t := range in {
 		ok, err := pred(ctx, t)
 		if err != nil {
 			return nil, err
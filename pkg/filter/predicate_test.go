@@ -11,6 +11,7 @@ import (
 
 type TestPackage struct {
 	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
 	Deprecated bool           `json:"deprecated,omitempty"`
 	Signature  *TestSignature `json:"signature,omitempty"`
 	Tags       []string       `json:"tags"`
@@ -21,6 +22,7 @@ type TestSignature struct {
 }
 
 func (p TestPackage) GetName() string     { return p.Name }
+func (p TestPackage) GetVersion() string  { return p.Version }
 func (p TestPackage) GetDeprecated() bool { return p.Deprecated }
 
 type TestPackageVersion struct {
@@ -41,6 +43,21 @@ func TestAnyOf(t *testing.T) {
 	assert.True(t, ok)
 }
 
+func TestAllOf(t *testing.T) {
+	preds := filter.AllOf(
+		filter.MatchesName[TestPackage]("foo"),
+		filter.MatchesDeprecated[TestPackage](true),
+	)
+
+	ctx := context.Background()
+	ok, err := preds(ctx, TestPackage{Name: "foo", Deprecated: true})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	ok, err = preds(ctx, TestPackage{Name: "foo"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
 func TestFilterSlice(t *testing.T) {
 	pred := filter.MatchesDeprecated[TestPackageVersion](true)
 
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type HasVersion interface {
+	HasName
+	GetVersion() string
+}
+
+// VersionComparer orders two versions like strings.Compare, using an ecosystem's rules.
+type VersionComparer func(a, b string) (int, error)
+
+var versionConstraintRE = regexp.MustCompile(`^(<<|<=|=|>=|>>)\s*(\S+)$`)
+
+type versionConstraint struct {
+	op      string
+	version string
+}
+
+// MatchesVersions requires packages to satisfy the constraints for their name, like `openssl: ">= 1.1.1n-0+deb11u3, << 3"`.
+// Constraints are separated by commas, and use the Debian operators `<<`, `<=`, `=`, `>=` and `>>`.
+// Packages without constraints always match.
+func MatchesVersions[T HasVersion](constraints map[string]string, cmp VersionComparer) (Predicate[T], error) {
+	parsed := make(map[string][]versionConstraint, len(constraints))
+	for name, s := range constraints {
+		for _, c := range strings.Split(s, ",") {
+			m := versionConstraintRE.FindStringSubmatch(strings.TrimSpace(c))
+			if m == nil {
+				return nil, fmt.Errorf("invalid version constraint for %s: %q", name, c)
+			}
+			parsed[name] = append(parsed[name], versionConstraint{op: m[1], version: m[2]})
+		}
+	}
+
+	return func(_ context.Context, pkg T) (bool, error) {
+		for _, c := range parsed[pkg.GetName()] {
+			res, err := cmp(pkg.GetVersion(), c.version)
+			if err != nil {
+				return false, fmt.Errorf("comparing %s version %q: %w", pkg.GetName(), pkg.GetVersion(), err)
+			}
+			if !c.satisfiedBy(res) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}, nil
+}
+
+func (c versionConstraint) satisfiedBy(cmp int) bool {
+	switch c.op {
+	case "<<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "=":
+		return cmp == 0
+	case ">=":
+		return cmp >= 0
+	case ">>":
+		return cmp > 0
+	default:
+		return false
+	}
+}
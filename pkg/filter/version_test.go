@@ -0,0 +1,68 @@
+package filter_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/filter"
+)
+
+// compareInts compares versions that are plain integers.
+func compareInts(a, b string) (int, error) {
+	ai, err := strconv.Atoi(a)
+	if err != nil {
+		return 0, err
+	}
+	bi, err := strconv.Atoi(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ai < bi:
+		return -1, nil
+	case ai > bi:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+func TestMatchesVersions(t *testing.T) {
+	pred, err := filter.MatchesVersions[TestPackage](map[string]string{
+		"foo": ">= 2, << 10",
+		"bar": "= 3",
+	}, compareInts)
+	require.NoError(t, err)
+
+	cases := []struct {
+		pkg      TestPackage
+		expected bool
+	}{
+		{TestPackage{Name: "foo", Version: "1"}, false},
+		{TestPackage{Name: "foo", Version: "2"}, true},
+		{TestPackage{Name: "foo", Version: "9"}, true},
+		{TestPackage{Name: "foo", Version: "10"}, false},
+		{TestPackage{Name: "bar", Version: "3"}, true},
+		{TestPackage{Name: "bar", Version: "4"}, false},
+		{TestPackage{Name: "baz", Version: "1"}, true},
+	}
+	ctx := context.Background()
+	for _, tc := range cases {
+		ok, err := pred(ctx, tc.pkg)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, ok, "%s=%s", tc.pkg.Name, tc.pkg.Version)
+	}
+
+	_, err = pred(ctx, TestPackage{Name: "foo", Version: "invalid"})
+	assert.Error(t, err)
+}
+
+func TestMatchesVersions_Invalid(t *testing.T) {
+	for _, constraint := range []string{"", "2", "~> 2", ">= 2,", "> 2"} {
+		_, err := filter.MatchesVersions[TestPackage](map[string]string{"foo": constraint}, compareInts)
+		assert.Error(t, err, constraint)
+	}
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -32,9 +33,25 @@ func NewCachedMux(tracer trace.Tracer, cache cached.ByteStorage) *CachedMux {
 type HttpRequest struct {
 	Path     string
 	PathVars map[string]string
+	Method   string
+	// Host, Header and Body are excluded from cache keys.
+	Host   string      `json:"-"`
+	Header http.Header `json:"-"`
+	// Body is the unread request body, for routes registered with RegisterWithBody. Handlers must limit how much
+	// they read.
+	Body io.Reader `json:"-"`
 }
 
 func (h CachedMux) Register(path string, ttl time.Duration, handler cached.Function[HttpRequest, *hedge.HttpResponse]) {
+	h.register(path, ttl, handler, false)
+}
+
+// RegisterWithBody registers a handler that reads the request body, like an upload. Responses are not cached.
+func (h CachedMux) RegisterWithBody(path string, handler cached.Function[HttpRequest, *hedge.HttpResponse]) {
+	h.register(path, 0, handler, true)
+}
+
+func (h CachedMux) register(path string, ttl time.Duration, handler cached.Function[HttpRequest, *hedge.HttpResponse], withBody bool) {
 	if ttl > 0 {
 		cache := cached.WithPrefix(fmt.Sprintf("mux:%s", path), h.cache)
 		handler = cached.Wrap(cache, handler,
@@ -52,10 +69,17 @@ func (h CachedMux) Register(path string, ttl time.Duration, handler cached.Funct
 			span.SetAttributes(attribute.String(fmt.Sprintf("mux.vars.%s", k), v))
 		}
 
-		res, err := handler(ctx, HttpRequest{
+		req := HttpRequest{
 			Path:     path,
 			PathVars: vars,
-		})
+			Method:   r.Method,
+			Host:     r.Host,
+			Header:   r.Header,
+		}
+		if withBody {
+			req.Body = r.Body
+		}
+		res, err := handler(ctx, req)
 		if err != nil {
 			_ = observability.CaptureError(span, err)
 			w.WriteHeader(http.StatusInternalServerError)
@@ -3,8 +3,10 @@ package base_test
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -51,3 +53,32 @@ func TestCachedMux(t *testing.T) {
 	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
 	assert.Equal(t, `{"counter":2,"key":"bar"}`, res.Body.String())
 }
+
+func TestCachedMux_Body(t *testing.T) {
+	h := base.NewCachedMux(observability.NoopTracer, cached.InMemory[string, []byte]())
+	h.RegisterWithBody("/upload", func(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+		b, _ := io.ReadAll(req.Body)
+		body, _ := json.Marshal(map[string]string{
+			"method": req.Method,
+			"token":  req.Header.Get("Authorization"),
+			"body":   string(b),
+		})
+		return &hedge.HttpResponse{Body: body}, nil
+	})
+	h.Register("/other", 0, func(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+		assert.Nil(t, req.Body)
+		return &hedge.HttpResponse{}, nil
+	})
+
+	req := httptest.NewRequest("PUT", "/upload", strings.NewReader("hello"))
+	req.Header.Set("Authorization", "Bearer token")
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"method":"PUT","token":"Bearer token","body":"hello"}`, res.Body.String())
+
+	// Other routes don't receive the body:
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("PUT", "/other", strings.NewReader("hello")))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
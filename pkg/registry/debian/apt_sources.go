@@ -0,0 +1,125 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// HandleAptSources serves a deb822 `.sources` file for a repository, for `/etc/apt/sources.list.d`.
+// Every suite is a stanza with the components and architectures of its Release file, signed by the repository's
+// public keyring inline.
+func (h Handler) HandleAptSources(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	parent, ok := h.repos[req.PathVars["repository"]]
+	if !ok {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	keyring, err := parent.armoredPublicKeys(h.now())
+	if err != nil {
+		return nil, err
+	}
+
+	repos := []*repositoryHandler{parent}
+	if parent.suites != nil {
+		// Suites are listed once, by codename:
+		repos = repos[:0]
+		for _, rh := range parent.suites {
+			if !containsRepository(repos, rh) {
+				repos = append(repos, rh)
+			}
+		}
+		sort.Slice(repos, func(i, j int) bool { return repos[i].name < repos[j].name })
+	}
+
+	stanzas := make([]Fields, 0, len(repos))
+	for _, rh := range repos {
+		release, err := h.loadRelease(ctx, rh)
+		if err != nil {
+			return nil, fmt.Errorf("loading release of %s: %w", rh.name, err)
+		}
+		types := "deb"
+		if rh.sources != nil {
+			types = "deb deb-src"
+		}
+		stanzas = append(stanzas, Fields{
+			{Key: "Types", Value: types},
+			{Key: "URIs", Value: aptURI(req)},
+			// Suites are served at dists/{repository}, or dists/{repository}/{codename}:
+			{Key: "Suites", Value: rh.name},
+			{Key: "Components", Value: strings.Join(release.Components, " ")},
+			{Key: "Architectures", Value: strings.Join(release.Architectures, " ")},
+			{Key: "Signed-By", Value: strings.TrimSuffix(string(keyring), "\n")},
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := writeAptSources(&buf, stanzas...); err != nil {
+		return nil, err
+	}
+	return &hedge.HttpResponse{
+		Body: buf.Bytes(),
+	}, nil
+}
+
+// aptURI is the base URI of the Debian ecosystem, as the client requested it.
+func aptURI(req base.HttpRequest) string {
+	scheme := req.Header.Get("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+	}
+	return fmt.Sprintf("%s://%s/debian", scheme, req.Host)
+}
+
+func containsRepository(repos []*repositoryHandler, rh *repositoryHandler) bool {
+	for _, r := range repos {
+		if r == rh {
+			return true
+		}
+	}
+	return false
+}
+
+// writeAptSources writes stanzas in the order of their fields. Multi-line values are continued on indented lines,
+// with blank lines written as ".".
+func writeAptSources(out io.Writer, stanzas ...Fields) error {
+	for i, stanza := range stanzas {
+		if i > 0 {
+			if _, err := fmt.Fprintln(out); err != nil {
+				return fmt.Errorf("writing sources: %w", err)
+			}
+		}
+		for _, f := range stanza {
+			if f.Value == "" {
+				continue
+			}
+			if !strings.Contains(f.Value, "\n") {
+				if _, err := fmt.Fprintf(out, "%s: %s\n", f.Key, f.Value); err != nil {
+					return fmt.Errorf("writing sources: %w", err)
+				}
+				continue
+			}
+
+			if _, err := fmt.Fprintf(out, "%s:\n", f.Key); err != nil {
+				return fmt.Errorf("writing sources: %w", err)
+			}
+			for _, line := range strings.Split(f.Value, "\n") {
+				if line == "" {
+					line = "."
+				}
+				if _, err := fmt.Fprintf(out, " %s\n", line); err != nil {
+					return fmt.Errorf("writing sources: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
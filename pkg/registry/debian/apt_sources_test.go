@@ -0,0 +1,75 @@
+package debian_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+func TestHandler_AptSources(t *testing.T) {
+	mirror := newTestMirror(t)
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+	aptSources := func(t *testing.T, h http.Handler) []debian.Paragraph {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/debian/sources/test.sources", nil)
+		req.Host = "hedge.example.com:8080"
+		req.Header.Set("X-Forwarded-Proto", "https")
+		res := httptest.NewRecorder()
+		h.ServeHTTP(res, req)
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.True(t, strings.HasPrefix(res.Body.String(), "Types: deb\nURIs: https://hedge.example.com:8080/debian\n"))
+		graphs, err := debian.ParseControlFile(res.Body)
+		require.NoError(t, err)
+		for _, graph := range graphs {
+			// The inline keyring is the repository's public key:
+			armored := strings.ReplaceAll(graph["Signed-By"], "\n.\n", "\n\n")
+			keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(armored))
+			require.NoError(t, err)
+			require.Len(t, keyring, 1)
+			assert.Equal(t, readTestKey(t)[0].PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+		}
+		return graphs
+	}
+
+	t.Run("repository", func(t *testing.T) {
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		graphs := aptSources(t, h)
+		require.Len(t, graphs, 1)
+		assert.Equal(t, "test", graphs[0]["Suites"])
+		assert.Equal(t, "main contrib", graphs[0]["Components"])
+		assert.Equal(t, "amd64", graphs[0]["Architectures"])
+	})
+
+	t.Run("suites", func(t *testing.T) {
+		repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.Suites = []debian.SuiteConfig{{Codename: "next", Release: "test"}, {Codename: "current", Suite: "stable", Release: "test"}}
+		graphs := aptSources(t, newTestHandler(t, repoCfg, policies))
+		require.Len(t, graphs, 2)
+		assert.Equal(t, "test/current", graphs[0]["Suites"])
+		assert.Equal(t, "test/next", graphs[1]["Suites"])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		assert.Equal(t, http.StatusNotFound, get(t, h, "/debian/sources/other.sources").Code)
+		assert.Equal(t, http.StatusNotFound, get(t, h, "/debian/keys/other.gpg").Code)
+	})
+}
+
+func TestHandler_Keyring(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), map[string]string{"testpkg.cue": `name: "testpkg"`})
+	res := get(t, h, "/debian/keys/test.gpg")
+	require.Equal(t, http.StatusOK, res.Code)
+	keyring, err := openpgp.ReadKeyRing(res.Body)
+	require.NoError(t, err)
+	require.Len(t, keyring, 1)
+	assert.Equal(t, readTestKey(t)[0].PrimaryKey.Fingerprint, keyring[0].PrimaryKey.Fingerprint)
+	assert.Nil(t, keyring[0].PrivateKey)
+}
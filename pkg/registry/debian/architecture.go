@@ -0,0 +1,117 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// ArchitectureAll is the architecture of packages that install on every architecture, like documentation.
+// Repositories may list `all` in their Architectures and publish these packages in `binary-all` indices.
+// Hedge serves them in every architecture's Packages index instead, which every version of apt understands.
+// Reference: https://wiki.debian.org/DebianRepository/Format#Architectures
+const ArchitectureAll = Architecture("all")
+
+// servedRelease removes `all` from a release's architectures, as its packages are served in every architecture.
+// NoSupportForArchitectureAll is kept for loaders, it is not rendered without `all`.
+func servedRelease(release *hedge.DebianRelease) *hedge.DebianRelease {
+	if !contains(release.Architectures, string(ArchitectureAll)) {
+		return release
+	}
+	served := proto.Clone(release).(*hedge.DebianRelease)
+	served.Architectures = served.Architectures[:0]
+	for _, a := range release.Architectures {
+		if a != string(ArchitectureAll) {
+			served.Architectures = append(served.Architectures, a)
+		}
+	}
+	return served
+}
+
+// hasArchitectureAllIndex reports whether a release publishes `all` packages in a separate index, instead of (or as
+// well as) every architecture's index.
+func hasArchitectureAllIndex(release *hedge.DebianRelease, fn string) bool {
+	if release.NoSupportForArchitectureAll {
+		return false
+	}
+	for _, compression := range upstreamIndexCompressions {
+		if _, ok := release.Digests[fn+compression.Extension()]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// withArchitectureAll adds the packages of the `binary-all` index to every architecture's packages.
+func withArchitectureAll(wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		pkgs, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if args.Architecture == ArchitectureAll || !hasArchitectureAllIndex(args.Release, fmt.Sprintf("%s/binary-%s/Packages", args.Component, ArchitectureAll)) {
+			return pkgs, nil
+		}
+		allArgs := args
+		allArgs.Architecture = ArchitectureAll
+		allPkgs, err := wrapped(ctx, allArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		// The architecture's index may already have some `all` packages:
+		type packageKey struct{ name, version string }
+		seen := make(map[packageKey]struct{}, len(pkgs.Packages))
+		for _, pkg := range pkgs.Packages {
+			seen[packageKey{name: pkg.Name, version: pkg.Version}] = struct{}{}
+		}
+		merged := append([]*hedge.DebianPackage(nil), pkgs.Packages...)
+		for _, pkg := range allPkgs.Packages {
+			if pkg.Architecture != string(ArchitectureAll) {
+				continue
+			}
+			if _, ok := seen[packageKey{name: pkg.Name, version: pkg.Version}]; !ok {
+				merged = append(merged, pkg)
+			}
+		}
+		return &hedge.DebianPackages{Packages: merged}, nil
+	}
+}
+
+// contentsWithArchitectureAll adds the files of the `Contents-all` index to every architecture's Contents index.
+func contentsWithArchitectureAll(wrapped cached.Function[LoadPackagesArgs, *hedge.DebianContents]) cached.Function[LoadPackagesArgs, *hedge.DebianContents] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianContents, error) {
+		contents, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		if args.Architecture == ArchitectureAll || !hasArchitectureAllIndex(args.Release, fmt.Sprintf("%s/Contents-%s", args.Component, ArchitectureAll)) {
+			return contents, nil
+		}
+		allArgs := args
+		allArgs.Architecture = ArchitectureAll
+		allContents, err := wrapped(ctx, allArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		// Files in both indices are listed once, with the packages of both:
+		entries := make(map[string]*hedge.DebianContents_Entry, len(contents.Entries))
+		merged := &hedge.DebianContents{Entries: make([]*hedge.DebianContents_Entry, 0, len(contents.Entries)+len(allContents.Entries))}
+		for _, entry := range append(append([]*hedge.DebianContents_Entry(nil), contents.Entries...), allContents.Entries...) {
+			if existing, ok := entries[entry.Path]; ok {
+				existing.Packages = append(existing.Packages, entry.Packages...)
+				continue
+			}
+			entry := proto.Clone(entry).(*hedge.DebianContents_Entry)
+			entries[entry.Path] = entry
+			merged.Entries = append(merged.Entries, entry)
+		}
+		sort.SliceStable(merged.Entries, func(i, j int) bool { return merged.Entries[i].Path < merged.Entries[j].Path })
+		return merged, nil
+	}
+}
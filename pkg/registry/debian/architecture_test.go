@@ -0,0 +1,99 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+func TestHandler_ArchitectureAll(t *testing.T) {
+	var allPackages bytes.Buffer
+	require.NoError(t, debian.WriteControlFile(&allPackages, debian.ParagraphFromPackage(&hedge.DebianPackage{
+		Name:         "testpkg-doc",
+		Version:      "1.2.3",
+		Architecture: "all",
+		Filename:     "pool/main/t/testpkg/testpkg-doc_1.2.3_all.deb",
+		Size:         1,
+		Sha256:       make([]byte, 32),
+	})))
+	policies := map[string]string{"testpkg.cue": `name: =~"^testpkg"`}
+	packageNames := func(t *testing.T, h http.Handler) []string {
+		t.Helper()
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, []string{"amd64"}, release.Architectures)
+		assert.NotContains(t, release.Digests, "main/binary-all/Packages")
+		assert.False(t, release.NoSupportForArchitectureAll)
+
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		var names []string
+		for _, pkg := range pkgs {
+			names = append(names, pkg.Name)
+		}
+		return names
+	}
+
+	for label, architectures := range map[string][]string{
+		"default":       nil,
+		"configured":    {"amd64"},
+		"all in config": {"all", "amd64"},
+	} {
+		t.Run(label, func(t *testing.T) {
+			mirror := newTestMirror(t)
+			mirror.SetAllPackages(t, map[string][]byte{"main": allPackages.Bytes()}, false)
+			repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+			repoCfg.Source.Upstream.Architectures = architectures
+			h := newTestHandler(t, repoCfg, policies)
+			assert.ElementsMatch(t, []string{"testpkg", "testpkg-doc"}, packageNames(t, h))
+			res := get(t, h, "/debian/dists/test/main/binary-all/Packages")
+			assert.Equal(t, http.StatusNotFound, res.Code)
+		})
+	}
+
+	t.Run("no support for architecture all", func(t *testing.T) {
+		// The binary-amd64 index already has the `all` packages, so binary-all is not fetched:
+		mirror := newTestMirror(t)
+		mirror.SetAllPackages(t, map[string][]byte{"main": allPackages.Bytes()}, true)
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		assert.Equal(t, []string{"testpkg"}, packageNames(t, h))
+	})
+}
+
+func TestWriteReleaseFile_NoSupportForArchitectureAll(t *testing.T) {
+	for label, tc := range map[string]struct {
+		architectures []string
+		expected      string
+	}{
+		"with all":    {architectures: []string{"all", "amd64"}, expected: "No-Support-for-Architecture-all: Packages\n"},
+		"without all": {architectures: []string{"amd64"}},
+	} {
+		t.Run(label, func(t *testing.T) {
+			release := &hedge.DebianRelease{Architectures: tc.architectures, NoSupportForArchitectureAll: true}
+			graph, err := debian.ParagraphFromRelease(release)
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			require.NoError(t, debian.WriteControlFile(&buf, graph))
+			if tc.expected == "" {
+				assert.NotContains(t, buf.String(), "No-Support-for-Architecture-all")
+				return
+			}
+			assert.Contains(t, buf.String(), tc.expected)
+
+			graphs, err := debian.ParseControlFile(strings.NewReader(buf.String()))
+			require.NoError(t, err)
+			parsed, err := debian.ReleaseFromParagraph(graphs[0])
+			require.NoError(t, err)
+			assert.True(t, parsed.NoSupportForArchitectureAll)
+		})
+	}
+}
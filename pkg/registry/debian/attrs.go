@@ -1,6 +1,10 @@
 package debian
 
-import "go.opentelemetry.io/otel/attribute"
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
 
 func attrDist(dist string) attribute.KeyValue {
 	return attribute.String("debian,dist", dist)
@@ -14,10 +18,38 @@ func attrComponent(component string) attribute.KeyValue {
 	return attribute.String("debian.component", component)
 }
 
-func attrComponents(components []string) attribute.KeyValue {
-	return attribute.StringSlice("debian.components", components)
-}
-
 func attrPackageCount(count int) attribute.KeyValue {
 	return attribute.Int("debian.package.count", count)
 }
+
+func attrFilename(fn string) attribute.KeyValue {
+	return attribute.String("debian.filename", fn)
+}
+
+func attrMirror(mirror string) attribute.KeyValue {
+	return attribute.String("debian.mirror", mirror)
+}
+
+func attrMirrorLatency(d time.Duration) attribute.KeyValue {
+	return attribute.Int64("debian.mirror.latency_ms", d.Milliseconds())
+}
+
+func attrMirrorHealthy(healthy bool) attribute.KeyValue {
+	return attribute.Bool("debian.mirror.healthy", healthy)
+}
+
+func attrRepository(repo string) attribute.KeyValue {
+	return attribute.String("debian.repository", repo)
+}
+
+func attrSnapshot(id string) attribute.KeyValue {
+	return attribute.String("debian.snapshot", id)
+}
+
+func attrFileCount(count int) attribute.KeyValue {
+	return attribute.Int("debian.file.count", count)
+}
+
+func attrLanguage(language string) attribute.KeyValue {
+	return attribute.String("debian.language", language)
+}
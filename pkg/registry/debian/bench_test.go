@@ -0,0 +1,108 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+const (
+	contribPackages = 297
+	// mainCopies of "contrib" are about the size of bullseye's main/amd64 Packages: ~59,000 packages and ~46 MB.
+	// On one core of a Xeon, ControlReader reads it in ~230ms, and EachPackage parses every package in ~800ms.
+	mainCopies = 200
+)
+
+type benchmarkInput struct {
+	name     string
+	packages []byte
+	count    int
+}
+
+// readBenchmarkInputs returns uncompressed Packages files, so benchmarks don't measure decompression: the
+// "contrib" Packages file, and "main" which repeats it to the size of main/amd64.
+func readBenchmarkInputs(b *testing.B) []benchmarkInput {
+	b.Helper()
+	compressed, err := os.ReadFile("testdata/bullseye_Packages.gz")
+	require.NoError(b, err)
+	var contrib bytes.Buffer
+	require.NoError(b, debian.CompressionGZIP.Decompress(&contrib, bytes.NewReader(compressed)))
+
+	var main bytes.Buffer
+	for i := 0; i < mainCopies; i++ {
+		main.Write(contrib.Bytes())
+		main.WriteString("\n")
+	}
+	return []benchmarkInput{
+		{name: "contrib", packages: contrib.Bytes(), count: contribPackages},
+		{name: "main", packages: main.Bytes(), count: mainCopies * contribPackages},
+	}
+}
+
+func BenchmarkParseControlFile(b *testing.B) {
+	for _, in := range readBenchmarkInputs(b) {
+		b.Run(in.name, func(b *testing.B) {
+			b.SetBytes(int64(len(in.packages)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				graphs, err := debian.ParseControlFile(bytes.NewReader(in.packages))
+				require.NoError(b, err)
+				require.Len(b, graphs, in.count)
+			}
+		})
+	}
+}
+
+func BenchmarkControlReader(b *testing.B) {
+	for _, in := range readBenchmarkInputs(b) {
+		b.Run(in.name, func(b *testing.B) {
+			b.SetBytes(int64(len(in.packages)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var count int
+				for r := debian.NewControlReader(bytes.NewReader(in.packages)); ; count++ {
+					_, err := r.Next()
+					if errors.Is(err, io.EOF) {
+						break
+					}
+					require.NoError(b, err)
+				}
+				require.Equal(b, in.count, count)
+			}
+		})
+	}
+}
+
+func BenchmarkParser_EachPackage(b *testing.B) {
+	ctx := context.Background()
+	parser := debian.NewParser(observability.NoopTracer)
+	for _, in := range readBenchmarkInputs(b) {
+		b.Run(in.name, func(b *testing.B) {
+			b.SetBytes(int64(len(in.packages)))
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				var count int
+				err := parser.EachPackage(ctx, bytes.NewReader(in.packages), func(*hedge.DebianPackage) error {
+					count++
+					return nil
+				})
+				require.NoError(b, err)
+				require.Equal(b, in.count, count)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package debian
+
+import (
+	"context"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// DependencyClosure adds the packages from index that are required to install the selected packages.
+// Pre-Depends and Depends are followed. A dependency is satisfied by a package that is already included, or by the
+// first alternative that is found in the index by name or by Provides, with a matching version.
+// Dependencies that can not be resolved against the index are ignored.
+func DependencyClosure(selected, index []*hedge.DebianPackage) []*hedge.DebianPackage {
+	byName := map[string][]provision{}
+	providers := map[string][]provision{}
+	for _, pkg := range index {
+		byName[pkg.Name] = append(byName[pkg.Name], provision{pkg: pkg, version: pkg.Version})
+		for _, p := range pkg.Provides {
+			providers[p.Name] = append(providers[p.Name], providedBy(pkg, p))
+		}
+	}
+
+	included := map[string]struct{}{}
+	available := map[string][]provision{}
+	var closure, queue []*hedge.DebianPackage
+	add := func(pkg *hedge.DebianPackage) {
+		if _, ok := included[pkg.Filename]; ok {
+			return
+		}
+		included[pkg.Filename] = struct{}{}
+		available[pkg.Name] = append(available[pkg.Name], provision{pkg: pkg, version: pkg.Version})
+		for _, p := range pkg.Provides {
+			available[p.Name] = append(available[p.Name], providedBy(pkg, p))
+		}
+		closure = append(closure, pkg)
+		queue = append(queue, pkg)
+	}
+	resolve := func(dep *hedge.DebianDependency) {
+		for _, alt := range dep.Alternatives {
+			if len(satisfying(alt, available[alt.Name])) > 0 {
+				return
+			}
+		}
+		for _, alt := range dep.Alternatives {
+			if candidates := satisfying(alt, byName[alt.Name]); len(candidates) > 0 {
+				for _, pkg := range candidates {
+					add(pkg)
+				}
+				return
+			}
+			if candidates := satisfying(alt, providers[alt.Name]); len(candidates) > 0 {
+				add(candidates[0])
+				return
+			}
+		}
+	}
+
+	for _, pkg := range selected {
+		add(pkg)
+	}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		for _, dep := range pkg.PreDepends {
+			resolve(dep)
+		}
+		for _, dep := range pkg.Depends {
+			resolve(dep)
+		}
+	}
+	return closure
+}
+
+// provision is a package that can satisfy a relation, as the version of a real or virtual package.
+type provision struct {
+	pkg     *hedge.DebianPackage
+	version string
+}
+
+func providedBy(pkg *hedge.DebianPackage, provides *hedge.DebianRelation) provision {
+	// Only `Provides: foo (= 1.0)` provides a version:
+	if provides.VersionOperator == "=" {
+		return provision{pkg: pkg, version: provides.Version}
+	}
+	return provision{pkg: pkg}
+}
+
+func satisfying(rel *hedge.DebianRelation, candidates []provision) []*hedge.DebianPackage {
+	var pkgs []*hedge.DebianPackage
+	for _, c := range candidates {
+		if satisfiesRelation(rel, c.version) {
+			pkgs = append(pkgs, c.pkg)
+		}
+	}
+	return pkgs
+}
+
+// withDependencies adds the dependency closure of the filtered packages, resolved against the upstream packages of
+// every component in the release.
+func withDependencies(filtered, upstream cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		var selected, index, component []*hedge.DebianPackage
+		for _, c := range args.Release.Components {
+			componentArgs := args
+			componentArgs.Component = Component(c)
+			upstreamPkgs, err := upstream(ctx, componentArgs)
+			if err != nil {
+				return nil, err
+			}
+			filteredPkgs, err := filtered(ctx, componentArgs)
+			if err != nil {
+				return nil, err
+			}
+			index = append(index, upstreamPkgs.Packages...)
+			selected = append(selected, filteredPkgs.Packages...)
+			if componentArgs.Component == args.Component {
+				component = upstreamPkgs.Packages
+			}
+		}
+
+		// Return the closure in upstream order, restricted to the requested component:
+		inClosure := map[string]struct{}{}
+		for _, pkg := range DependencyClosure(selected, index) {
+			inClosure[pkg.Filename] = struct{}{}
+		}
+		pkgs := make([]*hedge.DebianPackage, 0, len(inClosure))
+		for _, pkg := range component {
+			if _, ok := inClosure[pkg.Filename]; ok {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		return &hedge.DebianPackages{Packages: pkgs}, nil
+	}
+}
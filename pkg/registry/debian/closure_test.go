@@ -0,0 +1,76 @@
+package debian_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+func closurePackage(t *testing.T, name, version, depends, provides string) *hedge.DebianPackage {
+	t.Helper()
+	deps, err := debian.ParseDependencies(depends)
+	require.NoError(t, err)
+	rels, err := debian.ParseRelations(provides)
+	require.NoError(t, err)
+	return &hedge.DebianPackage{
+		Name:     name,
+		Version:  version,
+		Filename: "pool/main/" + name + "_" + version + ".deb",
+		Depends:  deps,
+		Provides: rels,
+	}
+}
+
+func closureNames(closure []*hedge.DebianPackage) []string {
+	names := make([]string, 0, len(closure))
+	for _, pkg := range closure {
+		names = append(names, pkg.Name+"="+pkg.Version)
+	}
+	return names
+}
+
+func TestDependencyClosure(t *testing.T) {
+	app := closurePackage(t, "app", "1.0", "libapp (>= 1.0), mail-transport-agent, libc6", "")
+	index := []*hedge.DebianPackage{
+		app,
+		closurePackage(t, "libapp", "1.2", "libc6 | libc6.1", ""),
+		closurePackage(t, "libc6", "2.31-13", "", ""),
+		closurePackage(t, "libc6.1", "2.31-13", "", ""),
+		closurePackage(t, "exim4", "4.94", "", "mail-transport-agent"),
+		closurePackage(t, "postfix", "3.5", "", "mail-transport-agent"),
+		closurePackage(t, "unrelated", "1.0", "", ""),
+	}
+
+	closure := debian.DependencyClosure([]*hedge.DebianPackage{app}, index)
+	assert.Equal(t, []string{"app=1.0", "libapp=1.2", "exim4=4.94", "libc6=2.31-13"}, closureNames(closure))
+}
+
+func TestDependencyClosure_SatisfiedByProvides(t *testing.T) {
+	app := closurePackage(t, "app", "1.0", "mail-transport-agent", "")
+	postfix := closurePackage(t, "postfix", "3.5", "", "mail-transport-agent")
+	index := []*hedge.DebianPackage{
+		app,
+		closurePackage(t, "exim4", "4.94", "", "mail-transport-agent"),
+		postfix,
+	}
+
+	closure := debian.DependencyClosure([]*hedge.DebianPackage{app, postfix}, index)
+	assert.Equal(t, []*hedge.DebianPackage{app, postfix}, closure)
+}
+
+func TestDependencyClosure_Versions(t *testing.T) {
+	app := closurePackage(t, "app", "1.0", "libapp (>= 2.0~rc1) | libapp-compat (= 1.0), libabi (>= 3)", "")
+	index := []*hedge.DebianPackage{
+		app,
+		closurePackage(t, "libapp", "1.9", "", ""),
+		closurePackage(t, "libapp-shim", "1.0", "", "libapp-compat (= 1.0)"),
+		closurePackage(t, "libabi-unversioned", "1.0", "", "libabi"),
+		closurePackage(t, "libabi3", "3.1", "", "libabi (= 3.1)"),
+	}
+
+	closure := debian.DependencyClosure([]*hedge.DebianPackage{app}, index)
+	assert.Equal(t, []string{"app=1.0", "libapp-shim=1.0", "libabi3=3.1"}, closureNames(closure))
+}
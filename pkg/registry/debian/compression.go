@@ -1,11 +1,14 @@
 package debian
 
 import (
+	"compress/bzip2"
 	"compress/gzip"
 	"fmt"
 	"io"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/ulikunitz/xz"
+	"github.com/ulikunitz/xz/lzma"
 )
 
 type Compression string
@@ -14,7 +17,11 @@ var (
 	CompressionNone = Compression("")
 	CompressionGZIP = Compression("gzip")
 	// CompressionXZ is way too slow.
-	CompressionXZ = Compression("xz")
+	CompressionXZ   = Compression("xz")
+	CompressionZSTD = Compression("zstd")
+	// CompressionBZIP2 and CompressionLZMA are only found in old .deb archives, and can only be decompressed.
+	CompressionBZIP2 = Compression("bzip2")
+	CompressionLZMA  = Compression("lzma")
 )
 
 func CompressionFromExtension(extension string) Compression {
@@ -23,6 +30,12 @@ func CompressionFromExtension(extension string) Compression {
 		return CompressionGZIP
 	case ".xz":
 		return CompressionXZ
+	case ".zst":
+		return CompressionZSTD
+	case ".bz2":
+		return CompressionBZIP2
+	case ".lzma":
+		return CompressionLZMA
 	default:
 		return CompressionNone
 	}
@@ -36,6 +49,12 @@ func (c Compression) Extension() string {
 		return ".gz"
 	case CompressionXZ:
 		return ".xz"
+	case CompressionZSTD:
+		return ".zst"
+	case CompressionBZIP2:
+		return ".bz2"
+	case CompressionLZMA:
+		return ".lzma"
 	default:
 		return ""
 	}
@@ -58,6 +77,25 @@ func (c Compression) Compress(dst io.Writer, src io.Reader) error {
 		defer w.Close()
 		dst = w
 
+	case CompressionZSTD:
+		w, err := zstd.NewWriter(dst)
+		if err != nil {
+			return fmt.Errorf("creating zstd writer: %w", err)
+		}
+		defer w.Close()
+		dst = w
+
+	case CompressionLZMA:
+		w, err := lzma.NewWriter(dst)
+		if err != nil {
+			return fmt.Errorf("creating lzma writer: %w", err)
+		}
+		defer w.Close()
+		dst = w
+
+	case CompressionBZIP2:
+		return fmt.Errorf("compressing %s is not supported", c)
+
 	default:
 		return fmt.Errorf("unknown compression: %s", c)
 	}
@@ -67,28 +105,53 @@ func (c Compression) Compress(dst io.Writer, src io.Reader) error {
 }
 
 func (c Compression) Decompress(dst io.Writer, src io.Reader) error {
+	r, err := c.Reader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	_, err = io.Copy(dst, r)
+	return err
+}
+
+// Reader decompresses src as it is read.
+func (c Compression) Reader(src io.Reader) (io.ReadCloser, error) {
 	switch c {
 	case CompressionNone:
+		return io.NopCloser(src), nil
 
 	case CompressionGZIP:
 		r, err := gzip.NewReader(src)
 		if err != nil {
-			return fmt.Errorf("creating gzip reader: %w", err)
+			return nil, fmt.Errorf("creating gzip reader: %w", err)
 		}
-		defer r.Close()
-		src = r
+		return r, nil
 
 	case CompressionXZ:
 		r, err := xz.NewReader(src)
 		if err != nil {
-			return fmt.Errorf("creating gzip reader: %w", err)
+			return nil, fmt.Errorf("creating xz reader: %w", err)
 		}
-		src = r
+		return io.NopCloser(r), nil
+
+	case CompressionZSTD:
+		r, err := zstd.NewReader(src)
+		if err != nil {
+			return nil, fmt.Errorf("creating zstd reader: %w", err)
+		}
+		return r.IOReadCloser(), nil
+
+	case CompressionBZIP2:
+		return io.NopCloser(bzip2.NewReader(src)), nil
+
+	case CompressionLZMA:
+		r, err := lzma.NewReader(src)
+		if err != nil {
+			return nil, fmt.Errorf("creating lzma reader: %w", err)
+		}
+		return io.NopCloser(r), nil
 
 	default:
-		return fmt.Errorf("unknown compression: %s", c)
+		return nil, fmt.Errorf("unknown compression: %s", c)
 	}
-
-	_, err := io.Copy(dst, src)
-	return err
 }
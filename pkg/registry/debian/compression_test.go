@@ -15,6 +15,8 @@ func TestCompression(t *testing.T) {
 		debian.CompressionNone: 5,
 		debian.CompressionGZIP: 29,
 		debian.CompressionXZ:   64,
+		debian.CompressionZSTD: 18,
+		debian.CompressionLZMA: 28,
 	}
 
 	for compression, expectedSize := range cases {
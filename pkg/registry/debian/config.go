@@ -1,34 +1,179 @@
 package debian
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/thepwagner/hedge/pkg/filter"
 	"github.com/thepwagner/hedge/pkg/registry"
 	"github.com/thepwagner/hedge/proto/hedge/v1"
 )
 
 type RepositoryConfig struct {
-	Source   SourceConfig  `yaml:"source"`
+	Source SourceConfig `yaml:"source"`
+	// Sources are merged into one repository, instead of Source.
+	Sources []SourceConfig `yaml:"sources"`
+	// Conflicts chooses between packages with the same name and architecture from different Sources.
+	Conflicts ConflictResolution `yaml:"conflicts"`
+	// Suites are upstream releases served by this repository, each from Source with its own release.
+	Suites []SuiteConfig `yaml:"suites"`
+
+	// Policies select packages from their index metadata. Upstream indices don't list files or maintainer scripts,
+	// so packages allowed by Policies are checked again with their contents when they are fetched from the pool.
+	// Use ContentPolicies to require files or maintainer scripts.
 	Policies filter.Config `yaml:"policies"`
+	// ComponentPolicies replace Policies for the named components.
+	ComponentPolicies map[string]filter.Config `yaml:"componentPolicies"`
+	// ContentPolicies are applied to every package served from the pool, including dependencies, once its files and
+	// maintainer scripts are known. They don't select packages for the indices.
+	ContentPolicies *filter.Config `yaml:"contentPolicies"`
+	// IncludeDependencies adds the dependencies of allowed packages, even if they are not allowed by policy.
+	IncludeDependencies bool `yaml:"includeDependencies"`
+	// SourcePackages serves Sources indices for `deb-src`. Sources of allowed packages are allowed.
+	SourcePackages bool `yaml:"sourcePackages"`
+	// SourcePolicies allow additional source packages, regardless of their binary packages.
+	SourcePolicies *filter.Config `yaml:"sourcePolicies"`
+	// Contents serves Contents indices for `apt-file`, limited to allowed packages.
+	Contents bool `yaml:"contents"`
+	// Translations are the languages of i18n/Translation indices to serve, limited to allowed packages.
+	Translations []string `yaml:"translations"`
+	// Retention keeps allowed package versions after upstream replaces them.
+	Retention *RetentionConfig `yaml:"retention"`
 
 	NameRaw string `yaml:"name"`
+	// KeyPath is a private key that signs the repository.
 	KeyPath string `yaml:"keyPath"`
+	// Keys are private keys that sign the repository during their validity window, so keys can be rotated with a
+	// period where both keys sign.
+	Keys []SigningKeyConfig `yaml:"keys"`
+	// ValidFor is how long clients trust a Release file signed by hedge, before they must fetch a new one.
+	// Defaults to a week. hedge signs a new Release file after half of it.
+	ValidFor time.Duration `yaml:"validFor"`
+}
+
+// SuiteConfig is a release of a repository with Suites, like `bullseye` or `bullseye-updates`.
+// Each suite is served at `dists/{repository}/{codename}`, and at `dists/{repository}/{suite}` if Suite is set.
+type SuiteConfig struct {
+	// Codename identifies the suite, like `bullseye`. It is the upstream release unless Release is set.
+	Codename string `yaml:"codename"`
+	// Suite is an alias of the codename, like `stable`.
+	Suite string `yaml:"suite"`
+	// Release is the upstream release, if it is not the codename.
+	Release string `yaml:"release"`
+}
+
+// validateSuites checks that Suites can be served from Source, and that every codename and suite is unique.
+func (c RepositoryConfig) validateSuites() error {
+	if c.Source.Upstream == nil || len(c.Sources) > 0 {
+		return fmt.Errorf("suites require an upstream source")
+	}
+	names := make(map[string]struct{}, 2*len(c.Suites))
+	for _, suite := range c.Suites {
+		if suite.Codename == "" {
+			return fmt.Errorf("suite has no codename")
+		}
+		for _, name := range []string{suite.Codename, suite.Suite} {
+			if name == "" {
+				continue
+			}
+			// "pool" would be ambiguous with the repository's pool, slashes with the suite's files:
+			if name == "pool" || strings.Contains(name, "/") {
+				return fmt.Errorf("invalid suite name %q", name)
+			}
+			if _, ok := names[name]; ok {
+				return fmt.Errorf("suite %q is listed more than once", name)
+			}
+			names[name] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// suiteConfig returns the configuration of a suite, which is the repository's configuration with the suite's
+// upstream release.
+func (c RepositoryConfig) suiteConfig(suite SuiteConfig) *RepositoryConfig {
+	upstream := *c.Source.Upstream
+	upstream.Release = suite.Codename
+	if suite.Release != "" {
+		upstream.Release = suite.Release
+	}
+	c.Source.Upstream = &upstream
+	c.Suites = nil
+	return &c
+}
+
+// RetentionConfig keeps package versions in the repository's indices and pool after upstream removes them.
+// A version is kept while it is one of the newest Versions of its package, or was allowed within Period.
+// Retained versions must still be allowed by the repository's policies. hedge stores the pool file of each version
+// once it is allowed, versions whose file upstream removed before it was stored are not retained.
+type RetentionConfig struct {
+	// Versions is how many versions of each package to keep, including the versions upstream still has.
+	Versions int `yaml:"versions"`
+	// Period is how long to keep a version after upstream removes it.
+	Period time.Duration `yaml:"period"`
+}
+
+// SigningKeyConfig is a private key, optionally limited to a validity window.
+type SigningKeyConfig struct {
+	Path      string    `yaml:"path"`
+	NotBefore time.Time `yaml:"notBefore"`
+	NotAfter  time.Time `yaml:"notAfter"`
 }
 
 var _ registry.RepositoryConfig = (*RepositoryConfig)(nil)
 
-func (c RepositoryConfig) Name() string                { return c.NameRaw }
-func (c *RepositoryConfig) SetName(name string)        { c.NameRaw = name }
-func (c RepositoryConfig) FilterConfig() filter.Config { return c.Policies }
+func (c RepositoryConfig) Name() string         { return c.NameRaw }
+func (c *RepositoryConfig) SetName(name string) { c.NameRaw = name }
+
+func (c RepositoryConfig) PolicyNames() []string {
+	names := append([]string(nil), c.Policies.PolicyNames()...)
+	for _, cfg := range c.ComponentPolicies {
+		names = append(names, cfg.PolicyNames()...)
+	}
+	if c.SourcePolicies != nil {
+		names = append(names, c.SourcePolicies.PolicyNames()...)
+	}
+	if c.ContentPolicies != nil {
+		names = append(names, c.ContentPolicies.PolicyNames()...)
+	}
+	for _, src := range c.Sources {
+		if src.Policies != nil {
+			names = append(names, src.Policies.PolicyNames()...)
+		}
+	}
+	return names
+}
 
 // SourceConfig defines where packages are stored.
 type SourceConfig struct {
 	Upstream *UpstreamConfig
 	GitHub   *GitHubConfig
+	Hosted   *HostedConfig
+
+	// Name identifies one of a repository's Sources.
+	Name string
+	// Priority wins conflicts between Sources, higher first.
+	Priority int
+	// Policies replace the repository's policies for packages from one of a repository's Sources.
+	Policies *filter.Config
 }
 
+// ConflictResolution chooses between packages from different sources.
+type ConflictResolution string
+
+const (
+	// ConflictsByPriority keeps the packages from the source with the highest Priority. This is the default.
+	ConflictsByPriority ConflictResolution = "priority"
+	// ConflictsByVersion keeps the package with the highest version, then by Priority.
+	ConflictsByVersion ConflictResolution = "version"
+)
+
 // UpstreamConfig is a Debian repository acting as a source.
 type UpstreamConfig struct {
-	URL           string
+	URL string
+	// Mirrors are tried after URL, if it is slow or unavailable.
+	Mirrors       []string
 	Key           string
 	Release       string
 	Architectures []string
@@ -37,6 +182,31 @@ type UpstreamConfig struct {
 
 // GitHubConfig polls GitHub releases for packages.
 type GitHubConfig struct {
+	// URL is the GitHub Enterprise server, if not github.com.
+	URL          string
 	Release      *hedge.DebianRelease
 	Repositories []string
+	// Releases is how many of the newest releases of each repository are served, so versions can be pinned.
+	// Defaults to 10.
+	Releases int `yaml:"releases"`
+	// Prereleases serves releases marked as pre-releases. Drafts are never served.
+	Prereleases bool `yaml:"prereleases"`
+}
+
+// HostedConfig stores packages uploaded to hedge.
+type HostedConfig struct {
+	Release *hedge.DebianRelease
+	// TokensPath is a file of tokens that may upload packages, one per line.
+	TokensPath string `yaml:"tokensPath"`
+	// MaxUploadSize limits the size of uploaded packages, in bytes. Defaults to 100 MiB.
+	MaxUploadSize int64 `yaml:"maxUploadSize"`
+}
+
+// MirrorURLs are the URL and Mirrors, in order of preference.
+func (c UpstreamConfig) MirrorURLs() []string {
+	var urls []string
+	if c.URL != "" {
+		urls = append(urls, c.URL)
+	}
+	return append(urls, c.Mirrors...)
 }
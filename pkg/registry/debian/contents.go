@@ -0,0 +1,122 @@
+package debian
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Contents indices map files to the packages that contain them, for `apt-file`.
+// Reference: https://wiki.debian.org/DebianRepository/Format#A.22Contents.22_indices
+
+func (p Parser) Contents(ctx context.Context, in io.Reader) (*hedge.DebianContents, error) {
+	_, span := p.tracer.Start(ctx, "debian.Parser.Contents")
+	defer span.End()
+
+	var contents hedge.DebianContents
+	// Lines may be any length:
+	r := bufio.NewReaderSize(in, 64*1024)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, observability.CaptureError(span, fmt.Errorf("reading contents: %w", err))
+		}
+		if line == "" && err != nil {
+			break
+		}
+
+		// The path may contain spaces, the package list does not:
+		line = strings.TrimRight(line, " \t\r\n")
+		i := strings.LastIndexAny(line, " \t")
+		if i < 0 {
+			continue
+		}
+		filePath := strings.TrimRight(line[:i], " \t")
+		if filePath == "" {
+			continue
+		}
+		contents.Entries = append(contents.Entries, &hedge.DebianContents_Entry{
+			Path:     filePath,
+			Packages: strings.Split(line[i+1:], ","),
+		})
+	}
+	return &contents, nil
+}
+
+// WriteContentsFile renders a Contents index.
+func WriteContentsFile(w io.Writer, contents *hedge.DebianContents) error {
+	bw := bufio.NewWriter(w)
+	for _, entry := range contents.Entries {
+		if _, err := fmt.Fprintf(bw, "%-59s %s\n", entry.Path, strings.Join(entry.Packages, ",")); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// contentsFiltered removes packages that are not in the filtered Packages index from the upstream Contents index.
+// Files that are only in removed packages are removed.
+func contentsFiltered(tracer trace.Tracer, upstream cached.Function[LoadPackagesArgs, *hedge.DebianContents], packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianContents] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianContents, error) {
+		ctx, span := tracer.Start(ctx, "debian.contentsFiltered", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
+		defer span.End()
+
+		allowed, err := packageNames(ctx, packages, args)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+		contents, err := upstream(ctx, args)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+
+		var filtered hedge.DebianContents
+		for _, entry := range contents.Entries {
+			var pkgs []string
+			for _, pkg := range entry.Packages {
+				// Packages are qualified by section, like `editors/vim` or `contrib/games/alien-arena`:
+				if _, ok := allowed[path.Base(pkg)]; ok {
+					pkgs = append(pkgs, pkg)
+				}
+			}
+			if len(pkgs) > 0 {
+				filtered.Entries = append(filtered.Entries, &hedge.DebianContents_Entry{Path: entry.Path, Packages: pkgs})
+			}
+		}
+		return &filtered, nil
+	}
+}
+
+// packageNames returns the names of the packages in a Packages index.
+func packageNames(ctx context.Context, packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages], args LoadPackagesArgs) (map[string]struct{}, error) {
+	pkgs, err := packages(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(pkgs.Packages))
+	for _, pkg := range pkgs.Packages {
+		names[pkg.Name] = struct{}{}
+	}
+	return names, nil
+}
+
+// HandleContents serves the Contents index of a component and architecture, or any of a component's Contents
+// indices by hash.
+func (h Handler) HandleContents(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	component := req.PathVars["component"]
+	if _, byHash := req.PathVars["digest"]; byHash {
+		return h.serveRenderedByHash(ctx, req, component)
+	}
+	fn := fmt.Sprintf("%s/Contents-%s", component, req.PathVars["arch"])
+	return h.serveRendered(ctx, req, fn, CompressionFromExtension(req.PathVars["compression"]))
+}
@@ -0,0 +1,144 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	testContents = `usr/bin/vim.basic                                           editors/vim
+usr/share/doc/vim common/copyright                          editors/vim,editors/vim-tiny
+usr/lib/games/alien-arena/arena                             contrib/games/alien-arena
+`
+	testTranslations = `Package: vim
+Description-md5: 59e8b8f7757db8b53566d5d119872de8
+Description-en: Vi IMproved - enhanced vi editor
+ Vim is an almost compatible version of the UNIX editor Vi.
+ .
+ Many new features have been added.
+
+Package: vim-tiny
+Description-md5: 1a2b3c4d5e6f708192a3b4c5d6e7f809
+Description-en: Vi IMproved - enhanced vi editor - compact version
+`
+)
+
+func TestParser_Contents(t *testing.T) {
+	contents, err := debian.NewParser(observability.NoopTracer).Contents(context.Background(), strings.NewReader(testContents))
+	require.NoError(t, err)
+	require.Len(t, contents.Entries, 3)
+	assert.Equal(t, "usr/share/doc/vim common/copyright", contents.Entries[1].Path)
+	assert.Equal(t, []string{"editors/vim", "editors/vim-tiny"}, contents.Entries[1].Packages)
+	assert.Equal(t, []string{"contrib/games/alien-arena"}, contents.Entries[2].Packages)
+
+	var buf bytes.Buffer
+	require.NoError(t, debian.WriteContentsFile(&buf, contents))
+	assert.Equal(t, testContents, buf.String())
+}
+
+func TestParser_Translations(t *testing.T) {
+	translations, err := debian.NewParser(observability.NoopTracer).Translations(context.Background(), strings.NewReader(testTranslations))
+	require.NoError(t, err)
+	require.Len(t, translations.Entries, 2)
+	assert.Equal(t, "vim", translations.Entries[0].Name)
+	assert.Equal(t, "vim-tiny", translations.Entries[1].Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, debian.WriteTranslationFile(&buf, translations))
+	assert.Equal(t, testTranslations, buf.String())
+	parsed, err := debian.NewParser(observability.NoopTracer).Translations(context.Background(), &buf)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(translations, parsed))
+}
+
+func TestParser_LongLines(t *testing.T) {
+	long := strings.Repeat("x", 1024*1024)
+	parser := debian.NewParser(observability.NoopTracer)
+
+	contents, err := parser.Contents(context.Background(), strings.NewReader("usr/share/"+long+" editors/vim\r\nusr/bin/vim editors/vim"))
+	require.NoError(t, err)
+	require.Len(t, contents.Entries, 2)
+	assert.Equal(t, "usr/share/"+long, contents.Entries[0].Path)
+	assert.Equal(t, []string{"editors/vim"}, contents.Entries[1].Packages)
+
+	translation := "Package: vim\nDescription-md5: 0123456789abcdef0123456789abcdef\nDescription-en: Vi IMproved\n " + long + "\n .\n   verbatim\n"
+	translations, err := parser.Translations(context.Background(), strings.NewReader(translation+"\n\n"+testTranslations))
+	require.NoError(t, err)
+	require.Len(t, translations.Entries, 3)
+	assert.Equal(t, "vim", translations.Entries[0].Name)
+	assert.Equal(t, translation, translations.Entries[0].Paragraph)
+}
+
+func TestHandler_ContentsAndTranslations(t *testing.T) {
+	mirror := newTestMirror(t)
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+	repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+	repoCfg.Contents = true
+	repoCfg.Translations = []string{"en"}
+	h := newTestHandler(t, repoCfg, policies)
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+
+	// indexed fetches a file listed in the InRelease, by path and by hash:
+	indexed := func(t *testing.T, fn string) []byte {
+		t.Helper()
+		digest, ok := release.Digests[fn+".gz"]
+		require.True(t, ok)
+		res := get(t, h, "/debian/dists/test/"+digest.Path)
+		require.Equal(t, http.StatusOK, res.Code)
+		actualDigest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+
+		res = get(t, h, "/debian/dists/test/"+fn)
+		require.Equal(t, http.StatusOK, res.Code)
+		plain, ok := release.Digests[fn]
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("%x", plain.Sha256Sum), fmt.Sprintf("%x", sha256.Sum256(res.Body.Bytes())))
+		return res.Body.Bytes()
+	}
+
+	t.Run("contents", func(t *testing.T) {
+		contents, err := debian.NewParser(observability.NoopTracer).Contents(context.Background(), bytes.NewReader(indexed(t, "main/Contents-amd64")))
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(&hedge.DebianContents{Entries: []*hedge.DebianContents_Entry{
+			{Path: "usr/bin/testpkg", Packages: []string{"utils/testpkg"}},
+			{Path: "usr/share/doc/shared/README", Packages: []string{"utils/testpkg"}},
+		}}, contents), contents)
+
+		// contrib has no upstream Contents index, so it's empty:
+		assert.Empty(t, indexed(t, "contrib/Contents-amd64"))
+	})
+
+	t.Run("translations", func(t *testing.T) {
+		translations, err := debian.NewParser(observability.NoopTracer).Translations(context.Background(), bytes.NewReader(indexed(t, "main/i18n/Translation-en")))
+		require.NoError(t, err)
+		require.Len(t, translations.Entries, 1)
+		assert.Equal(t, "testpkg", translations.Entries[0].Name)
+		assert.Contains(t, translations.Entries[0].Paragraph, "\n .\n with a blank line\n")
+
+		res := get(t, h, "/debian/dists/test/main/i18n/Translation-de")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.NotContains(t, release.Digests, "main/Contents-amd64.gz")
+		assert.NotContains(t, release.Digests, "main/i18n/Translation-en.gz")
+		res := get(t, h, "/debian/dists/test/main/Contents-amd64")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		res = get(t, h, "/debian/dists/test/main/i18n/Translation-en")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -2,9 +2,10 @@ package debian
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
-	"regexp"
 	"sort"
 	"strings"
 )
@@ -21,69 +22,254 @@ type toParagraph interface {
 
 func (p Paragraph) Paragraph() (Paragraph, error) { return p, nil }
 
-var keyValueRE = regexp.MustCompile(`^([^\s:]+):(.*)$`)
-
 // multilineKeys maintain newlines in their values.
 var multilineKeys = map[string]struct{}{
 	"MD5Sum": {},
 	"SHA256": {},
+	// Sources:
+	"Files":            {},
+	"Checksums-Sha1":   {},
+	"Checksums-Sha256": {},
+	"Checksums-Sha512": {},
+	"Package-List":     {},
+	// Packages.diff/Index:
+	"SHA256-History":  {},
+	"SHA256-Patches":  {},
+	"SHA256-Download": {},
+	// deb822 .sources:
+	"Signed-By": {},
 }
 
-// ParseControlFile parses a Debian control file.
-func ParseControlFile(in io.Reader) ([]Paragraph, error) {
-	var graphs []Paragraph
-	var currentKey string
-	currentGraph := Paragraph{}
+// foldedKeys are wrapped over lines that are joined into one, like lists of dependencies.
+// Other keys keep their continuation lines, like a Description, so unknown fields are written back as they were read.
+var foldedKeys = map[string]struct{}{
+	"Tag": {},
+	// Relationships:
+	"Breaks":             {},
+	"Conflicts":          {},
+	"Depends":            {},
+	"Enhances":           {},
+	"Pre-Depends":        {},
+	"Provides":           {},
+	"Recommends":         {},
+	"Replaces":           {},
+	"Suggests":           {},
+	"Built-Using":        {},
+	"Static-Built-Using": {},
+	// Sources:
+	"Architecture":          {},
+	"Binary":                {},
+	"Build-Conflicts":       {},
+	"Build-Conflicts-Arch":  {},
+	"Build-Conflicts-Indep": {},
+	"Build-Depends":         {},
+	"Build-Depends-Arch":    {},
+	"Build-Depends-Indep":   {},
+	"Uploaders":             {},
+}
+
+// Field is a data field of a paragraph.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// Fields are the data fields of a paragraph, in the order they were read. The order is not kept by Paragraph, or
+// the packages and releases parsed from it: WriteControlFile writes fields in its own order.
+type Fields []Field
+
+// Paragraph indexes the fields by key. If a key is repeated, the last value is kept.
+func (f Fields) Paragraph() (Paragraph, error) {
+	graph := make(Paragraph, len(f))
+	for _, field := range f {
+		graph[field.Key] = field.Value
+	}
+	return graph, nil
+}
+
+// ControlReader reads the paragraphs of a Debian control file one at a time, so large indices are parsed without
+// holding them in memory. Lines may be any length.
+type ControlReader struct {
+	in *bufio.Reader
+	// long holds lines that don't fit in the reader's buffer.
+	long []byte
+	// values of the paragraph being read are concatenated, then converted to a single string.
+	values []byte
+	starts []int
+	// keys are shared between paragraphs, as every paragraph of an index has the same few keys.
+	keys map[string]string
+}
+
+// maxInternedKeys limits the keys shared between paragraphs, in case a file has unique keys in every paragraph.
+const maxInternedKeys = 1024
+
+func NewControlReader(in io.Reader) *ControlReader {
+	return &ControlReader{
+		in:   bufio.NewReaderSize(in, 64*1024),
+		keys: map[string]string{},
+	}
+}
 
-	scanner := bufio.NewScanner(in)
-	scanner.Buffer(make([]byte, 0, 512*1024), 512*1024)
-	for scanner.Scan() {
-		line := scanner.Text()
+// Next returns the next paragraph, or io.EOF if there are no more.
+func (r *ControlReader) Next() (Fields, error) {
+	fields := make(Fields, 0, cap(r.starts))
+	r.values = r.values[:0]
+	r.starts = r.starts[:0]
+	var multiline, folded bool
+	for {
+		line, err := r.readLine()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
 
 		// An empty line indicates the end of the current paragraph:
-		if len(line) == 0 && len(currentGraph) > 0 {
-			graphs = append(graphs, currentGraph)
-			currentGraph = Paragraph{}
-			currentKey = ""
+		if len(line) == 0 {
+			if len(fields) > 0 {
+				break
+			}
 			continue
 		}
 
-		// A line that matches "Key: Value" is a new field (Value may be empty)
-		if m := keyValueRE.FindStringSubmatch(line); len(m) > 0 {
-			key := string(m[1])
-			currentKey = key
-
-			val := strings.TrimSpace(string(m[2]))
-			currentGraph[key] = val
+		// A line that starts with a space or tab is a continuation of the current Value.
+		// Multiline keys maintain newlines, folded keys are treated as WordWrap, and other keys keep their lines as
+		// they were, without the leading space.
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(fields) == 0 {
+				continue
+			}
+			if folded {
+				r.values = append(r.values, line...)
+				continue
+			}
+			if !multiline {
+				r.values = append(r.values, '\n')
+				r.values = append(r.values, line[1:]...)
+				continue
+			}
+			if len(r.values) > r.starts[len(r.starts)-1] {
+				r.values = append(r.values, '\n')
+			}
+			r.values = append(r.values, bytes.TrimSpace(line)...)
 			continue
 		}
 
-		// A line that starts with a space or tab is a continuation of the current Value
-		// Some keys maintain newlines while others are treated as WordWrap.
-		if len(line) == 0 || (line[0] != ' ' && line[0] != '\t') {
+		// A line that matches "Key: Value" is a new field (Value may be empty)
+		sep := bytes.IndexByte(line, ':')
+		if sep <= 0 || !isFieldName(line[:sep]) {
 			continue
 		}
-		if _, ok := multilineKeys[currentKey]; ok {
-			var prefix string
-			if len(currentGraph[currentKey]) > 0 {
-				prefix = "\n"
+		key := r.key(line[:sep])
+		fields = append(fields, Field{Key: key})
+		_, multiline = multilineKeys[key]
+		_, folded = foldedKeys[key]
+		r.starts = append(r.starts, len(r.values))
+		r.values = append(r.values, bytes.TrimSpace(line[sep+1:])...)
+	}
+
+	if len(fields) == 0 {
+		return nil, io.EOF
+	}
+	values := string(r.values)
+	for i := range fields {
+		end := len(values)
+		if i+1 < len(r.starts) {
+			end = r.starts[i+1]
+		}
+		fields[i].Value = values[r.starts[i]:end]
+	}
+	return fields, nil
+}
+
+// nextRaw returns the lines of the next paragraph as they were read, with line endings normalized to "\n", or
+// io.EOF if there are no more. For indices like Translation, whose formatting is passed through.
+func (r *ControlReader) nextRaw() (string, error) {
+	r.values = r.values[:0]
+	for {
+		line, err := r.readLine()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return "", err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			if len(r.values) > 0 {
+				break
 			}
-			currentGraph[currentKey] += prefix + strings.TrimSpace(string(line))
-		} else {
-			currentGraph[currentKey] += string(line)
+			continue
+		}
+		r.values = append(r.values, line...)
+		r.values = append(r.values, '\n')
+	}
+	if len(r.values) == 0 {
+		return "", io.EOF
+	}
+	return string(r.values), nil
+}
+
+func isFieldName(b []byte) bool {
+	for _, c := range b {
+		switch c {
+		case ' ', '\t', '\f', '\r':
+			return false
 		}
 	}
-	if err := scanner.Err(); err != nil {
+	return true
+}
+
+// readLine returns the next line without its line ending. The line is only valid until the next read.
+func (r *ControlReader) readLine() ([]byte, error) {
+	line, err := r.in.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		r.long = append(r.long[:0], line...)
+		for errors.Is(err, bufio.ErrBufferFull) {
+			line, err = r.in.ReadSlice('\n')
+			r.long = append(r.long, line...)
+		}
+		line = r.long
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf("reading file: %w", err)
 	}
+	if len(line) == 0 {
+		return nil, io.EOF
+	}
+	// The last line may not be terminated, io.EOF is returned by the next read:
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	return bytes.TrimSuffix(line, []byte("\r")), nil
+}
+
+func (r *ControlReader) key(b []byte) string {
+	if key, ok := r.keys[string(b)]; ok {
+		return key
+	}
+	key := string(b)
+	if len(r.keys) < maxInternedKeys {
+		r.keys[key] = key
+	}
+	return key
+}
 
-	if len(currentGraph) > 0 {
-		graphs = append(graphs, currentGraph)
+// ParseControlFile parses a Debian control file.
+func ParseControlFile(in io.Reader) ([]Paragraph, error) {
+	var graphs []Paragraph
+	for r := NewControlReader(in); ; {
+		fields, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return graphs, nil
+		} else if err != nil {
+			return nil, err
+		}
+		graph, err := fields.Paragraph()
+		if err != nil {
+			return nil, err
+		}
+		graphs = append(graphs, graph)
 	}
-	return graphs, nil
 }
 
-// WriteConfigFile writes a Debian control file.
+// WriteControlFile writes a Debian control file. Fields are sorted by key, with Package first and digests last.
 func WriteControlFile[P toParagraph](out io.Writer, graphs ...P) error {
 	for i, toGraph := range graphs {
 		graph, err := toGraph.Paragraph()
@@ -116,9 +302,9 @@ func WriteControlFile[P toParagraph](out io.Writer, graphs ...P) error {
 			} else if strings.EqualFold(keyJ, "SHA256") {
 				return true
 			}
-			if keyI == "MD5Sum" {
+			if strings.EqualFold(keyI, "MD5Sum") {
 				return false
-			} else if keyJ == "MD5Sum" {
+			} else if strings.EqualFold(keyJ, "MD5Sum") {
 				return true
 			}
 			return strings.Compare(keys[i], keys[j]) < 0
@@ -131,8 +317,8 @@ func WriteControlFile[P toParagraph](out io.Writer, graphs ...P) error {
 			}
 
 			if _, ok := multilineKeys[k]; !ok {
-				if _, err := fmt.Fprintf(out, "%s: %s\n", k, v); err != nil {
-					return fmt.Errorf("writing single-line key: %w", err)
+				if err := writeContinuedValue(out, k, v); err != nil {
+					return err
 				}
 				continue
 			}
@@ -149,3 +335,28 @@ func WriteControlFile[P toParagraph](out io.Writer, graphs ...P) error {
 	}
 	return nil
 }
+
+// writeContinuedValue writes a field whose first line follows the key, and any further lines are continuation lines.
+// Empty lines are written as " .", as an empty line would end the paragraph.
+func writeContinuedValue(out io.Writer, k, v string) error {
+	first, rest, continued := strings.Cut(v, "\n")
+	if first == "" && continued {
+		if _, err := fmt.Fprintf(out, "%s:\n", k); err != nil {
+			return fmt.Errorf("writing single-line key: %w", err)
+		}
+	} else if _, err := fmt.Fprintf(out, "%s: %s\n", k, first); err != nil {
+		return fmt.Errorf("writing single-line key: %w", err)
+	}
+	if !continued {
+		return nil
+	}
+	for _, line := range strings.Split(rest, "\n") {
+		if line == "" {
+			line = "."
+		}
+		if _, err := fmt.Fprintf(out, " %s\n", line); err != nil {
+			return fmt.Errorf("writing continuation line: %w", err)
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package debian_test
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 
@@ -50,6 +51,23 @@ func TestParseControlFile(t *testing.T) {
 				},
 			},
 		},
+		"continued key": {
+			lines: []string{
+				"Description: synopsis",
+				" first paragraph",
+				" .",
+				"   verbatim",
+				"X-Unknown:",
+				" first",
+				" second",
+			},
+			expected: []debian.Paragraph{
+				{
+					"Description": "synopsis\nfirst paragraph\n.\n  verbatim",
+					"X-Unknown":   "\nfirst\nsecond",
+				},
+			},
+		},
 		"multiple paragraphs": {
 			lines: []string{
 				"Foo: bar",
@@ -116,6 +134,24 @@ func TestWriteControlFile(t *testing.T) {
 				"",
 			},
 		},
+		"continued keys": {
+			paragraphs: []debian.Paragraph{
+				{
+					"Description": "synopsis\nfirst paragraph\n.\n  verbatim",
+					"X-Unknown":   "\nfirst\nsecond",
+				},
+			},
+			expected: []string{
+				"Description: synopsis",
+				" first paragraph",
+				" .",
+				"   verbatim",
+				"X-Unknown:",
+				" first",
+				" second",
+				"",
+			},
+		},
 		"field ordering": {
 			paragraphs: []debian.Paragraph{
 				{
@@ -150,3 +186,40 @@ func TestWriteControlFile(t *testing.T) {
 		})
 	}
 }
+
+func TestControlReader(t *testing.T) {
+	long := strings.Repeat("x", 1024*1024)
+	in := strings.Join([]string{
+		"Package: test",
+		"Version: 1.0",
+		"Description: first line",
+		" " + long,
+		"Architecture: amd64",
+		"",
+		"",
+		"Package: second\r",
+		"SHA256:\r",
+		" abc 1 main/Packages\r",
+		" def 2 main/Packages.gz",
+	}, "\n")
+
+	r := debian.NewControlReader(strings.NewReader(in))
+	fields, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, debian.Fields{
+		{Key: "Package", Value: "test"},
+		{Key: "Version", Value: "1.0"},
+		{Key: "Description", Value: "first line\n" + long},
+		{Key: "Architecture", Value: "amd64"},
+	}, fields)
+
+	fields, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, debian.Fields{
+		{Key: "Package", Value: "second"},
+		{Key: "SHA256", Value: "abc 1 main/Packages\ndef 2 main/Packages.gz"},
+	}, fields)
+
+	_, err = r.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
@@ -1,38 +1,59 @@
 package debian
 
 import (
+	"bytes"
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v45/github"
-	"github.com/thepwagner/hedge/pkg/registry"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// type FixedReleaseLoader struct {
-// 	release Release
-// }
+const gitHubURL = "https://github.com/"
 
-// func (r FixedReleaseLoader) Load(context.Context) (*Release, error) {
-// 	release := r.release
-// 	release.DateRaw = time.Now().UTC().Format(time.RFC1123)
-// 	return &release, nil
-// }
+// defaultGitHubReleases is how many releases of each repository are served, if GitHubConfig.Releases is unset.
+const defaultGitHubReleases = 10
 
-type GitHubPackagesLoader struct {
-	tracer trace.Tracer
-	client *http.Client
-	github *github.Client
-	parser Parser
+// GitHubRepository serves .deb assets from the newest GitHub releases of a list of repositories.
+type GitHubRepository struct {
+	tracer   trace.Tracer
+	github   *github.Client
+	fetchURL cached.Function[string, []byte]
+	parser   Parser
 
-	ghRepos []githubRepoConfig
+	baseURL     string
+	release     *hedge.DebianRelease
+	ghRepos     []githubRepoConfig
+	releases    int
+	prereleases bool
 }
 
-func NewGitHubPackagesLoader(tracer trace.Tracer, client *http.Client, cfg GitHubConfig) GitHubPackagesLoader {
+var (
+	_ ReleaseLoader  = (*GitHubRepository)(nil)
+	_ PackagesLoader = (*GitHubRepository)(nil)
+	_ PoolLoader     = (*GitHubRepository)(nil)
+)
+
+func NewGitHubRepository(tracer trace.Tracer, client *http.Client, fetchURL cached.Function[string, []byte], cfg GitHubConfig) (*GitHubRepository, error) {
 	ghRepos := make([]githubRepoConfig, 0, len(cfg.Repositories))
 	for _, repo := range cfg.Repositories {
 		nwo := strings.SplitN(repo, "/", 2)
+		if len(nwo) != 2 || nwo[0] == "" || nwo[1] == "" {
+			return nil, fmt.Errorf("invalid repository %q, expected owner/name", repo)
+		}
 		ghRepos = append(ghRepos, githubRepoConfig{
 			owner: nwo[0],
 			name:  nwo[1],
@@ -40,84 +61,216 @@ func NewGitHubPackagesLoader(tracer trace.Tracer, client *http.Client, cfg GitHu
 	}
 
 	// TODO: auth goes here
+	baseURL := gitHubURL
 	gh := github.NewClient(client)
+	if cfg.URL != "" {
+		baseURL = strings.TrimSuffix(cfg.URL, "/") + "/"
+		var err error
+		if gh, err = github.NewEnterpriseClient(baseURL, baseURL, client); err != nil {
+			return nil, fmt.Errorf("creating GitHub client: %w", err)
+		}
+	}
 
-	return GitHubPackagesLoader{
-		tracer:  tracer,
-		parser:  NewParser(tracer),
-		client:  client,
-		github:  gh,
-		ghRepos: ghRepos,
+	release := cfg.Release
+	if release == nil {
+		release = &hedge.DebianRelease{}
 	}
+	releases := cfg.Releases
+	if releases < 0 {
+		return nil, fmt.Errorf("invalid releases %d", releases)
+	} else if releases == 0 {
+		releases = defaultGitHubReleases
+	}
+
+	return &GitHubRepository{
+		tracer:      tracer,
+		github:      gh,
+		fetchURL:    fetchURL,
+		parser:      NewParser(tracer),
+		baseURL:     baseURL,
+		release:     release,
+		ghRepos:     ghRepos,
+		releases:    releases,
+		prereleases: cfg.Prereleases,
+	}, nil
 }
 
 type githubRepoConfig struct {
 	owner, name string
 }
 
-func (gh GitHubPackagesLoader) BaseURL() string { return "https://github.com/" }
-
-func (gh GitHubPackagesLoader) LoadPackages(ctx context.Context, arch Architecture) ([]registry.Package, error) {
-	// ctx, span := gh.tracer.Start(ctx, "githubloader.LoadPackages")
-	// defer span.End()
-
-	// archRE := regexp.MustCompile(fmt.Sprintf("[-_]%s\\.deb$", arch))
-	// var packages []registry.Package
-	// for _, repo := range gh.ghRepos {
-	// 	releases, _, err := gh.github.Repositories.ListReleases(ctx, repo.owner, repo.name, nil)
-	// 	if err != nil {
-	// 		span.RecordError(err)
-	// 		return nil, fmt.Errorf("listing releases for %s/%s: %w", repo.owner, repo.name, err)
-	// 	}
-	// 	for _, r := range releases {
-	// 		for _, asset := range r.Assets {
-	// 			if archRE.MatchString(asset.GetName()) {
-	// 				debURL := asset.GetBrowserDownloadURL()
-	// 				req, err := http.NewRequest("GET", debURL, nil)
-	// 				if err != nil {
-	// 					span.RecordError(err)
-	// 					return nil, fmt.Errorf("creating request for %s: %w", debURL, err)
-	// 				}
-	// 				req = req.WithContext(ctx)
-	// 				resp, err := gh.client.Do(req)
-	// 				if err != nil {
-	// 					span.RecordError(err)
-	// 					return nil, fmt.Errorf("downloading %s: %w", debURL, err)
-	// 				}
-	// 				defer resp.Body.Close()
-
-	// 				if resp.StatusCode != http.StatusOK {
-	// 					span.SetStatus(codes.Error, fmt.Sprintf("downloading %s: %s", debURL, resp.Status))
-	// 					return nil, fmt.Errorf("downloading %s: %s", debURL, resp.Status)
-	// 				}
-
-	// 				pkgData, err := ioutil.ReadAll(resp.Body)
-	// 				if err != nil {
-	// 					span.RecordError(err)
-	// 					return nil, fmt.Errorf("reading %s: %w", debURL, err)
-	// 				}
-
-	// 				pkg, err := gh.parser.PackageFromDeb(ctx, bytes.NewReader(pkgData))
-	// 				if err != nil {
-	// 					span.RecordError(err)
-	// 					return nil, fmt.Errorf("parsing %s: %w", debURL, err)
-	// 				}
-
-	// 				if pkg != nil {
-	// 					pkg.MD5Sum = fmt.Sprintf("%x", md5.Sum(pkgData))
-	// 					pkg.Sha256 = fmt.Sprintf("%x", sha256.Sum256(pkgData))
-	// 					pkg.SizeRaw = fmt.Sprintf("%d", len(pkgData))
-	// 					// FIXME: dist is hack
-	// 					pkg.Filename = fmt.Sprintf("dists/github/pool/%s", strings.TrimPrefix(debURL, "https://github.com/"))
-
-	// 					packages = append(packages, *pkg)
-	// 				}
-	// 			}
-	// 		}
-	// 		break
-	// 	}
-	// }
-
-	return nil, nil
-	// return packages, nil
+// LoadRelease synthesizes a release from the configured template.
+// The Date is when the newest served GitHub release was published, so the release only changes with its assets.
+func (gh *GitHubRepository) LoadRelease(ctx context.Context, args LoadReleaseArgs) (*hedge.DebianRelease, error) {
+	ctx, span := gh.tracer.Start(ctx, "debian.GitHubRepository.LoadRelease", trace.WithAttributes(attrDist(args.Dist)))
+	defer span.End()
+
+	var date time.Time
+	for _, repo := range gh.ghRepos {
+		releases, err := gh.listReleases(ctx, repo)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+		for _, r := range releases {
+			if published := r.GetPublishedAt().Time; published.After(date) {
+				date = published
+			}
+		}
+	}
+
+	release := proto.Clone(gh.release).(*hedge.DebianRelease)
+	release.MirrorUrl = gh.baseURL
+	release.Dist = args.Dist
+	release.Date = timestamppb.New(date.UTC().Truncate(time.Second))
+	if release.Codename == "" {
+		release.Codename = args.Dist
+	}
+	if len(args.Architectures) != 0 {
+		release.Architectures = args.Architectures
+	}
+	if len(args.Components) != 0 {
+		release.Components = args.Components
+	}
+	if len(release.Components) == 0 {
+		release.Components = []string{"main"}
+	}
+	return release, nil
+}
+
+// LoadPackages downloads and parses the assets that match the requested architecture.
+// Assets are published in the release's first component.
+func (gh *GitHubRepository) LoadPackages(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+	ctx, span := gh.tracer.Start(ctx, "debian.GitHubRepository.LoadPackages", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
+	defer span.End()
+
+	if len(args.Release.Components) == 0 || args.Release.Components[0] != string(args.Component) {
+		return &hedge.DebianPackages{}, nil
+	}
+
+	// `all` packages are served in every architecture:
+	archRE := regexp.MustCompile(fmt.Sprintf("[-_](%s|%s)\\.deb$", regexp.QuoteMeta(string(args.Architecture)), ArchitectureAll))
+	var packages []*hedge.DebianPackage
+	for _, repo := range gh.ghRepos {
+		releases, err := gh.listReleases(ctx, repo)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+		for _, release := range releases {
+			// Releases published after the requested release are served by the next one:
+			if release.GetPublishedAt().Time.Truncate(time.Second).After(args.Release.Date.AsTime()) {
+				continue
+			}
+			assets, err := gh.releaseAssets(ctx, repo, release, archRE)
+			if err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			packages = append(packages, assets...)
+		}
+	}
+	span.SetAttributes(attrPackageCount(len(packages)))
+	return &hedge.DebianPackages{Packages: packages}, nil
+}
+
+// listReleases returns the served releases of a repository, newest first.
+func (gh *GitHubRepository) listReleases(ctx context.Context, repo githubRepoConfig) ([]*github.RepositoryRelease, error) {
+	var served []*github.RepositoryRelease
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		releases, res, err := gh.github.Repositories.ListReleases(ctx, repo.owner, repo.name, opts)
+		if err != nil {
+			return nil, fmt.Errorf("listing releases for %s/%s: %w", repo.owner, repo.name, err)
+		}
+		for _, release := range releases {
+			if release.GetDraft() || (release.GetPrerelease() && !gh.prereleases) {
+				continue
+			}
+			served = append(served, release)
+			if len(served) == gh.releases {
+				return served, nil
+			}
+		}
+		if res.NextPage == 0 {
+			return served, nil
+		}
+		opts.Page = res.NextPage
+	}
+}
+
+// releaseAssets loads the packages of a release's assets that match archRE.
+func (gh *GitHubRepository) releaseAssets(ctx context.Context, repo githubRepoConfig, release *github.RepositoryRelease, archRE *regexp.Regexp) ([]*hedge.DebianPackage, error) {
+	var packages []*hedge.DebianPackage
+	for _, asset := range release.Assets {
+		if !archRE.MatchString(asset.GetName()) {
+			continue
+		}
+		filename := path.Join("pool", repo.owner, repo.name, "releases", "download", release.GetTagName(), asset.GetName())
+		pkg, err := gh.loadAsset(ctx, filename)
+		if err != nil {
+			return nil, err
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+func (gh *GitHubRepository) loadAsset(ctx context.Context, filename string) (*hedge.DebianPackage, error) {
+	ctx, span := gh.tracer.Start(ctx, "debian.GitHubRepository.loadAsset", trace.WithAttributes(attrFilename(filename)))
+	defer span.End()
+
+	u, err := gh.assetURL(filename)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	// Release assets are immutable, so they can be cached like pool files:
+	b, err := gh.fetchURL(cached.For(ctx, blobTTL), u)
+	if err != nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("downloading %s: %w", u, err))
+	}
+
+	pkg, err := gh.parser.PackageFromDeb(ctx, bytes.NewReader(b))
+	if err != nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("parsing %s: %w", u, err))
+	}
+	if pkg == nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("parsing %s: control file not found", u))
+	}
+
+	pkg.Filename = filename
+	pkg.Size = uint64(len(b))
+	md := md5.Sum(b)
+	pkg.Md5Sum = md[:]
+	sha := sha256.Sum256(b)
+	pkg.Sha256 = sha[:]
+	return pkg, nil
+}
+
+func (gh *GitHubRepository) LoadPoolFile(ctx context.Context, args LoadPoolFileArgs) ([]byte, error) {
+	ctx, span := gh.tracer.Start(ctx, "debian.GitHubRepository.LoadPoolFile", trace.WithAttributes(attrFilename(args.Filename)))
+	defer span.End()
+
+	u, err := gh.assetURL(args.Filename)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	b, err := gh.fetchURL(cached.For(ctx, blobTTL), u)
+	if err != nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("fetching pool file: %w", err))
+	}
+	if err := verifyFile(b, args.Size, args.Sha256); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return b, nil
+}
+
+// assetURL maps a pool filename like `pool/sigstore/cosign/releases/download/v1.10.0/cosign_1.10.0_amd64.deb` to its download URL.
+func (gh *GitHubRepository) assetURL(filename string) (string, error) {
+	assetPath := strings.TrimPrefix(filename, "pool/")
+	if assetPath == filename {
+		return "", fmt.Errorf("not a pool file: %s", filename)
+	}
+	u, err := url.JoinPath(gh.baseURL, assetPath)
+	if err != nil {
+		return "", fmt.Errorf("building URL: %w", err)
+	}
+	return u, nil
 }
@@ -1 +1,131 @@
 package debian_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// newTestGitHub serves a GitHub API where test/testpkg has releases: v1.2.3 has testpkg as an asset, v1.0.0 has tool.
+// A newer pre-release and draft have assets that can't be downloaded.
+func newTestGitHub(t *testing.T) *httptest.Server {
+	t.Helper()
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	tool := buildDeb(t, debian.CompressionXZ)
+
+	var srv *httptest.Server
+	release := func(tag, published string, prerelease, draft bool, assets ...string) map[string]interface{} {
+		var assetList []map[string]string
+		for _, asset := range assets {
+			assetList = append(assetList, map[string]string{"name": asset, "browser_download_url": srv.URL + "/test/testpkg/releases/download/" + tag + "/" + asset})
+		}
+		return map[string]interface{}{"tag_name": tag, "published_at": published, "prerelease": prerelease, "draft": draft, "assets": assetList}
+	}
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/test/testpkg/releases":
+			_ = json.NewEncoder(w).Encode([]map[string]interface{}{
+				release("v2.0.0", "2022-07-11T00:00:00Z", false, true, "testpkg_2.0.0_amd64.deb"),
+				release("v1.3.0-rc1", "2022-07-10T00:00:00Z", true, false, "testpkg_1.3.0_amd64.deb"),
+				release("v1.2.3", "2022-07-09T09:43:23Z", false, false, "testpkg_1.2.3_amd64.deb", "testpkg_1.2.3_arm64.deb", "checksums.txt"),
+				release("v1.0.0", "2022-07-01T00:00:00Z", false, false, "tool_1.0_amd64.deb"),
+			})
+		case "/test/testpkg/releases/download/v1.2.3/testpkg_1.2.3_amd64.deb":
+			_, _ = w.Write(deb)
+		case "/test/testpkg/releases/download/v1.0.0/tool_1.0_amd64.deb":
+			_, _ = w.Write(tool)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newTestGitHubHandler(t *testing.T, gh *httptest.Server, releases int) http.Handler {
+	t.Helper()
+	return newTestHandler(t, &debian.RepositoryConfig{
+		KeyPath: testPrivateKey,
+		Source: debian.SourceConfig{
+			GitHub: &debian.GitHubConfig{
+				URL: gh.URL,
+				Release: &hedge.DebianRelease{
+					Architectures: []string{"amd64"},
+					Origin:        "GitHub",
+					Codename:      "github",
+				},
+				Repositories: []string{"test/testpkg"},
+				Releases:     releases,
+			},
+		},
+		Policies: filter.Config{AnyOf: []string{"github.cue"}},
+	}, map[string]string{
+		"github.cue": `name: =~"^(testpkg|tool)$"`,
+	})
+}
+
+func TestHandler_GitHub(t *testing.T) {
+	gh := newTestGitHub(t)
+	h := newTestGitHubHandler(t, gh, 0)
+
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+	assert.Equal(t, "GitHub", release.Origin)
+	assert.Equal(t, "github", release.Codename)
+	assert.Equal(t, []string{"main"}, release.Components)
+	// The Date is the newest served release, not the time of the request:
+	assert.Equal(t, time.Date(2022, time.July, 9, 9, 43, 23, 0, time.UTC), release.Date.AsTime())
+	digest, ok := release.Digests["main/binary-amd64/Packages"]
+	require.True(t, ok)
+
+	res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	body := res.Body.Bytes()
+	actualDigest := sha256.Sum256(body)
+	assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+
+	pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Len(t, pkgs, 2)
+	assert.Equal(t, "testpkg", pkgs[0].Name)
+	assert.Equal(t, "dists/test/pool/test/testpkg/releases/download/v1.2.3/testpkg_1.2.3_amd64.deb", pkgs[0].Filename)
+	assert.Equal(t, "tool", pkgs[1].Name)
+	assert.Equal(t, "dists/test/pool/test/testpkg/releases/download/v1.0.0/tool_1.0_amd64.deb", pkgs[1].Filename)
+
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(len(deb)), pkgs[0].Size)
+	res = get(t, h, "/debian/"+pkgs[0].Filename)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, deb, res.Body.Bytes())
+
+	t.Run("newest release", func(t *testing.T) {
+		h := newTestGitHubHandler(t, gh, 1)
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+		assert.Equal(t, "testpkg", pkgs[0].Name)
+	})
+}
+
+func TestNewGitHubRepository_InvalidRepository(t *testing.T) {
+	_, err := debian.NewGitHubRepository(observability.NoopTracer, http.DefaultClient, nil, debian.GitHubConfig{
+		Repositories: []string{"cosign"},
+	})
+	assert.ErrorContains(t, err, "expected owner/name")
+}
@@ -1,16 +1,14 @@
 package debian
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"net/http"
-	"os"
+	"time"
 
-	"github.com/ProtonMail/go-crypto/openpgp"
-	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
-	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
 	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/pkg/registry"
 	"github.com/thepwagner/hedge/pkg/registry/base"
@@ -18,183 +16,563 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// defaultValidFor is how long clients trust a Release file signed by hedge.
+const defaultValidFor = 7 * 24 * time.Hour
+
 // Handler implements https://wiki.debian.org/DebianRepository/Format
 type Handler struct {
-	tracer trace.Tracer
-	repos  map[string]*repositoryHandler
-
-	releaseLoader  cached.Function[LoadReleaseArgs, *hedge.DebianRelease]
-	packagesLoader cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	tracer    trace.Tracer
+	repos     map[string]*repositoryHandler
+	blobs     cached.ByteStorage
+	snapshots *snapshotStore
+	pdiffs    *pdiffStore
+	// pins keep the pool files of retained versions and snapshots stored.
+	pins *pinnedFiles
+	// renderings list the rendered index files of each release, which are stored by digest in rendered.
+	renderings cached.ByteStorage
+	rendered   cached.ByteStorage
+	// contents are parsed pool files, by digest.
+	contents cached.ByteStorage
+	parser   Parser
+	now      func() time.Time
 }
 
 type repositoryHandler struct {
-	pk          *packet.PrivateKey
+	name string
+	keys []signingKey
+	// validFor is how long the signed Release file is valid.
+	validFor time.Duration
+	// snapshot is set when serving a snapshot of the repository, instead of the live repository.
+	snapshot *hedge.DebianSnapshot
+	// suites are the repositories served under this repository, by codename and suite. Set if the repository
+	// only serves suites.
+	suites map[string]*repositoryHandler
+	// configDigest changes when the repository's configuration or policies change, so packages are filtered and
+	// indices are rendered again.
+	configDigest []byte
+
+	// release loads the repository's release metadata, using releaseArgs.
+	release     cached.Function[LoadReleaseArgs, *hedge.DebianRelease]
 	releaseArgs LoadReleaseArgs
+
+	// upstream are all packages from the source, before policies are applied.
+	upstream cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	// packages are the upstream packages allowed by the repository's policies.
+	// Every endpoint must use this same filtered set, or the InRelease digests will not match.
+	packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	// sources are the source packages allowed by the repository's policies, if the repository serves them.
+	sources cached.Function[LoadSourcesArgs, *hedge.DebianSources]
+	// upstreamSources are all source packages from the source, before policies are applied.
+	upstreamSources cached.Function[LoadSourcesArgs, *hedge.DebianSources]
+	// contentsIndices are the Contents indices of allowed packages, if the repository serves them.
+	contentsIndices cached.Function[LoadPackagesArgs, *hedge.DebianContents]
+	// translations are the descriptions of allowed packages in languages, if the repository serves them.
+	translations cached.Function[LoadTranslationsArgs, *hedge.DebianTranslations]
+	languages    []string
+	// pool fetches files referenced by upstream packages.
+	pool PoolLoader
+	// policy is applied again to pool files, once their contents are known.
+	policy func(component Component, filename string) filter.Predicate[*hedge.DebianPackage]
+	// contentPolicy is applied to every pool file once its contents are known, if the repository has ContentPolicies.
+	contentPolicy filter.Predicate[*hedge.DebianPackage]
+	// hosted accepts uploads checked against hostedPolicy, if the repository has a hosted source.
+	hosted       *HostedRepository
+	hostedPolicy componentPolicy
 }
 
 func NewHandler(base *base.CachedMux, tracer trace.Tracer, cache cached.ByteStorage, client *http.Client, cfg registry.EcosystemConfig) (*Handler, error) {
 	h := &Handler{
-		tracer: tracer,
-		repos:  map[string]*repositoryHandler{},
+		tracer:     tracer,
+		repos:      map[string]*repositoryHandler{},
+		blobs:      cached.WithPrefix[string, []byte]("debian_blobs", cache),
+		snapshots:  newSnapshotStore(tracer, cache),
+		pdiffs:     newPDiffStore(tracer, cache),
+		renderings: cached.WithPrefix[string, []byte]("debian_renderings", cache),
+		rendered:   cached.WithPrefix[string, []byte]("debian_rendered", cache),
+		contents:   cached.WithPrefix[string, []byte]("debian_contents", cache),
+		parser:     NewParser(tracer),
+		now:        time.Now,
 	}
+	h.pins = newPinnedFiles(tracer, cache, h.blobs)
+
+	cachedFetch := cached.Wrap(cached.WithPrefix[string, []byte]("debian_urls", cache), cached.URLFetcher(client))
+	remote := NewRemoteRepository(tracer, cachedFetch)
+	remote.dates = newReleaseDates(cache)
+	loaders := sourceLoaders{
+		tracer:         tracer,
+		client:         client,
+		cache:          cache,
+		cachedFetch:    cachedFetch,
+		remoteReleases: observability.TracedFunc(tracer, "debian.LoadRelease", cached.Wrap(cached.WithPrefix[string, []byte]("debian_releases", cache), remote.LoadRelease, cached.AsProtoBuf[LoadReleaseArgs, *hedge.DebianRelease]())),
+		remotePackages: observability.TracedFunc(tracer, "debian.LoadPackages", cached.Wrap(cached.WithPrefix[string, []byte]("debian_packages", cache), remote.LoadPackages, cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianPackages]())),
+		remoteSources:  observability.TracedFunc(tracer, "debian.LoadSources", cached.Wrap(cached.WithPrefix[string, []byte]("debian_sources", cache), remote.LoadSources, cached.AsProtoBuf[LoadSourcesArgs, *hedge.DebianSources]())),
+		// Not "debian_contents", that's the parsed contents of pool files:
+		remoteContents:     observability.TracedFunc(tracer, "debian.LoadContents", cached.Wrap(cached.WithPrefix[string, []byte]("debian_contents_indices", cache), remote.LoadContents, cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianContents]())),
+		remoteTranslations: observability.TracedFunc(tracer, "debian.LoadTranslations", cached.Wrap(cached.WithPrefix[string, []byte]("debian_translations", cache), remote.LoadTranslations, cached.AsProtoBuf[LoadTranslationsArgs, *hedge.DebianTranslations]())),
+		// Pool files are cached by digest after verification, not by URL:
+		remotePool: NewRemoteRepository(tracer, cached.URLFetcher(client)),
+		retention:  newRetentionStore(tracer, cache, h.pins),
+		pin: func(rh *repositoryHandler, args LoadPackagesArgs, pkgs []*hedge.DebianPackage) {
+			h.pinPoolFiles(rh, args, pkgs, retainedTTL)
+		},
+	}
+
 	for repo, repoCfg := range cfg.Repositories {
 		debCfg := repoCfg.(*RepositoryConfig)
 
-		key, err := readKey(debCfg)
+		keys, err := readKeys(debCfg)
 		if err != nil {
-			return nil, fmt.Errorf("reading key for %s: %w", repo, err)
+			return nil, fmt.Errorf("reading keys for %s: %w", repo, err)
 		}
-
-		var releaseArgs LoadReleaseArgs
-		if debCfg.Source.Upstream != nil {
-			releaseArgs.MirrorURL = debCfg.Source.Upstream.URL
-			releaseArgs.Dist = debCfg.Source.Upstream.Release
-			releaseArgs.Architectures = debCfg.Source.Upstream.Architectures
-			releaseArgs.Components = debCfg.Source.Upstream.Components
-			releaseArgs.SigningKey = debCfg.Source.Upstream.Key
+		validFor := debCfg.ValidFor
+		if validFor == 0 {
+			validFor = defaultValidFor
+		}
+		if len(debCfg.Suites) == 0 {
+			rh, err := loaders.newRepository(repo, debCfg, cfg.Policies)
+			if err != nil {
+				return nil, err
+			}
+			rh.keys, rh.validFor = keys, validFor
+			h.repos[repo] = rh
+			continue
 		}
 
-		h.repos[repo] = &repositoryHandler{
-			pk:          key[0].PrivateKey,
-			releaseArgs: releaseArgs,
+		// Each suite is served as a repository, sharing the keys of the repository that lists it:
+		if err := debCfg.validateSuites(); err != nil {
+			return nil, fmt.Errorf("configuring suites of %s: %w", repo, err)
 		}
+		parent := &repositoryHandler{name: repo, keys: keys, validFor: validFor, suites: map[string]*repositoryHandler{}}
+		for _, suite := range debCfg.Suites {
+			rh, err := loaders.newRepository(fmt.Sprintf("%s/%s", repo, suite.Codename), debCfg.suiteConfig(suite), cfg.Policies)
+			if err != nil {
+				return nil, err
+			}
+			rh.keys, rh.validFor = keys, validFor
+			parent.suites[suite.Codename] = rh
+			if suite.Suite != "" {
+				parent.suites[suite.Suite] = rh
+			}
+		}
+		h.repos[repo] = parent
 	}
 
-	cachedFetch := cached.Wrap(cached.WithPrefix[string, []byte]("debian_urls", cache), cached.URLFetcher(client))
-	repo := NewRemoteRepository(tracer, cachedFetch)
-	h.releaseLoader = observability.TracedFunc(tracer, "debian.LoadRelease", cached.Wrap(cached.WithPrefix[string, []byte]("debian_releases", cache), repo.LoadRelease, cached.AsProtoBuf[LoadReleaseArgs, *hedge.DebianRelease]()))
-	h.packagesLoader = observability.TracedFunc(tracer, "debian.LoadPackages", cached.Wrap(cached.WithPrefix[string, []byte]("debian_packages", cache), repo.LoadPackages, cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianPackages]()))
-
-	base.Register("/debian/dists/{repository}/InRelease", 0, h.HandleInRelease)
-	base.Register("/debian/dists/{repository}/main/binary-{arch}/Packages{compression:(?:|.xz|.gz)}", 0, h.HandlePackages)
-	// r.HandleFunc("/debian/dists/{repository}/pool/{path:.*}", h.HandlePool)
+	// Snapshots are served with the same layout as the live repository, from a base URL pinned to a timestamp:
+	base.Register("/debian/snapshots/{repository}", 0, h.HandleSnapshots)
+	base.Register("/debian/snapshots/{repository}/{suite}", 0, h.HandleSnapshots)
+	base.Register("/debian/keys/{repository}.asc", 0, h.HandlePublicKey)
+	base.Register("/debian/keys/{repository}.gpg", 0, h.HandleKeyring)
+	base.Register("/debian/sources/{repository}.sources", 0, h.HandleAptSources)
+	base.RegisterWithBody("/debian/upload/{repository}", h.HandleUpload)
+	base.RegisterWithBody("/debian/upload/{repository}/{component}", h.HandleUpload)
+	for _, prefix := range []string{
+		"/debian/dists/{repository}",
+		"/debian/snapshots/{repository}/{snapshot}/dists/{dist}",
+		// Repositories with suites serve each suite as a repository:
+		"/debian/dists/{repository}/{suite}",
+		"/debian/snapshots/{repository}/{suite}/{snapshot}/dists/{dist}/{distSuite}",
+	} {
+		base.Register(prefix+"/InRelease", 0, h.HandleInRelease)
+		base.Register(prefix+"/Release", 0, h.HandleRelease)
+		base.Register(prefix+"/Release.gpg", 0, h.HandleReleaseSignature)
+		base.Register(prefix+"/pool/{path:.*}", 0, h.HandlePool)
+		base.Register(prefix+"/{component}/binary-{arch}/Packages{compression:(?:|.xz|.gz)}", 0, h.HandlePackages)
+		base.Register(prefix+"/{component}/binary-{arch}/by-hash/SHA256/{digest}", 0, h.HandleByHash)
+		base.Register(prefix+"/{component}/source/Sources{compression:(?:|.xz|.gz)}", 0, h.HandleSources)
+		base.Register(prefix+"/{component}/source/by-hash/SHA256/{digest}", 0, h.HandleSources)
+		base.Register(prefix+"/{component}/Contents-{arch:[^/.]+}{compression:(?:|.xz|.gz)}", 0, h.HandleContents)
+		base.Register(prefix+"/{component}/by-hash/SHA256/{digest}", 0, h.HandleContents)
+		base.Register(prefix+"/{component}/i18n/Translation-{language:[^/.]+}{compression:(?:|.xz|.gz)}", 0, h.HandleTranslation)
+		base.Register(prefix+"/{component}/i18n/by-hash/SHA256/{digest}", 0, h.HandleTranslation)
+		base.Register(prefix+"/{component}/binary-{arch}/Packages.diff/Index", 0, h.HandleDiffIndex)
+		base.Register(prefix+"/{component}/binary-{arch}/Packages.diff/by-hash/SHA256/{digest}", 0, h.HandleDiffIndex)
+		base.Register(prefix+"/{component}/binary-{arch}/Packages.diff/{patch}.gz", 0, h.HandleDiffPatch)
+	}
 	return h, nil
 }
 
-func (h Handler) HandleInRelease(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
-	rh, ok := h.repos[req.PathVars["repository"]]
-	if !ok {
-		return &hedge.HttpResponse{
-			StatusCode: http.StatusNotFound,
-		}, nil
+// newRepository configures a repository from its sources and policies. Keys are configured by the caller.
+func (l sourceLoaders) newRepository(repo string, debCfg *RepositoryConfig, policies map[string]string) (*repositoryHandler, error) {
+	rh := &repositoryHandler{name: repo}
+	var err error
+	if rh.configDigest, err = configDigest(policies, debCfg); err != nil {
+		return nil, fmt.Errorf("digesting config for %s: %w", repo, err)
 	}
-
-	// Load the release metadata:
-	release, err := h.releaseLoader(ctx, rh.releaseArgs)
+	repoPolicy, err := newComponentPolicy(policies, debCfg.Policies, debCfg.ComponentPolicies)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("loading policies for %s: %w", repo, err)
 	}
-	if release == nil {
-		return nil, fmt.Errorf("remote release not found")
+	if debCfg.ContentPolicies != nil {
+		if rh.contentPolicy, err = filter.CuePoliciesToPredicate[*hedge.DebianPackage](policies, *debCfg.ContentPolicies); err != nil {
+			return nil, fmt.Errorf("loading content policies for %s: %w", repo, err)
+		}
 	}
 
-	// The Release file contains hashes of all Packages files, so we need to load them:
-	packages := map[Architecture][]*hedge.DebianPackage{}
-	for _, a := range release.Architectures {
-		arch := Architecture(a)
-		pkgs, err := h.packagesLoader(ctx, LoadPackagesArgs{
-			Release:      release,
-			Architecture: arch,
-		})
+	var filtered cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	if len(debCfg.Sources) == 0 {
+		src, err := l.newSource(repo, debCfg.Source)
+		if err != nil {
+			return nil, fmt.Errorf("configuring source for %s: %w", repo, err)
+		}
+		rh.release = src.release
+		rh.releaseArgs = src.releaseArgs
+		rh.upstream = src.upstream
+		rh.pool = src.pool
+		rh.policy = func(component Component, _ string) filter.Predicate[*hedge.DebianPackage] {
+			return repoPolicy(component)
+		}
+		rh.hosted = src.hosted
+		rh.hostedPolicy = repoPolicy
+		filtered = policyFiltered(l.tracer, src.upstream, repoPolicy)
+		if debCfg.IncludeDependencies {
+			filtered = withDependencies(filtered, src.upstream)
+		}
+		if debCfg.SourcePackages {
+			if src.sources == nil {
+				return nil, fmt.Errorf("source of %s does not provide source packages", repo)
+			}
+			var pred filter.Predicate[*hedge.DebianSource]
+			if debCfg.SourcePolicies != nil {
+				if pred, err = filter.CuePoliciesToPredicate[*hedge.DebianSource](policies, *debCfg.SourcePolicies); err != nil {
+					return nil, fmt.Errorf("loading source policies for %s: %w", repo, err)
+				}
+			}
+			rh.upstreamSources = src.sources
+			rh.sources = cached.Wrap(cached.WithPrefix[string, []byte](fmt.Sprintf("debian_filtered_sources:%s:%x", repo, rh.configDigest), l.cache), sourcesServedFromPool(repo, sourcesFiltered(l.tracer, src.sources, filtered, pred)), cached.AsProtoBuf[LoadSourcesArgs, *hedge.DebianSources]())
+		}
+		if debCfg.Contents {
+			if src.contents == nil {
+				return nil, fmt.Errorf("source of %s does not provide contents indices", repo)
+			}
+			rh.contentsIndices = cached.Wrap(cached.WithPrefix[string, []byte](fmt.Sprintf("debian_filtered_contents_indices:%s:%x", repo, rh.configDigest), l.cache), contentsFiltered(l.tracer, src.contents, filtered), cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianContents]())
+		}
+		if len(debCfg.Translations) > 0 {
+			if src.translations == nil {
+				return nil, fmt.Errorf("source of %s does not provide translations", repo)
+			}
+			rh.languages = debCfg.Translations
+			rh.translations = cached.Wrap(cached.WithPrefix[string, []byte](fmt.Sprintf("debian_filtered_translations:%s:%x", repo, rh.configDigest), l.cache), translationsFiltered(l.tracer, src.translations, filtered), cached.AsProtoBuf[LoadTranslationsArgs, *hedge.DebianTranslations]())
+		}
+	} else {
+		if debCfg.Source.Upstream != nil || debCfg.Source.GitHub != nil || debCfg.Source.Hosted != nil {
+			return nil, fmt.Errorf("repository %s has both source and sources", repo)
+		}
+		if debCfg.SourcePackages {
+			return nil, fmt.Errorf("repository %s merges sources, which does not support source packages", repo)
+		}
+		if debCfg.Contents || len(debCfg.Translations) > 0 {
+			return nil, fmt.Errorf("repository %s merges sources, which does not support contents or translations", repo)
+		}
+		sources := make([]*source, 0, len(debCfg.Sources))
+		for _, srcCfg := range debCfg.Sources {
+			src, err := l.newSource(fmt.Sprintf("%s/%s", repo, srcCfg.Name), srcCfg)
+			if err != nil {
+				return nil, fmt.Errorf("configuring source %s for %s: %w", srcCfg.Name, repo, err)
+			}
+			src.name = srcCfg.Name
+			src.priority = srcCfg.Priority
+			src.policy = repoPolicy
+			if srcCfg.Policies != nil {
+				if src.policy, err = newComponentPolicy(policies, *srcCfg.Policies, nil); err != nil {
+					return nil, fmt.Errorf("loading policies for source %s of %s: %w", srcCfg.Name, repo, err)
+				}
+			}
+			src.filtered = policyFiltered(l.tracer, src.upstream, src.policy)
+			if src.hosted != nil {
+				if rh.hosted != nil {
+					return nil, fmt.Errorf("repository %s has more than one hosted source", repo)
+				}
+				rh.hosted = src.hosted
+				rh.hostedPolicy = src.policy
+			}
+			sources = append(sources, src)
+		}
+		merged, err := newMergedSource(l.tracer, repo, sources, debCfg.Conflicts)
+		if err != nil {
+			return nil, fmt.Errorf("merging sources for %s: %w", repo, err)
+		}
+		rh.release = merged.LoadRelease
+		rh.releaseArgs = LoadReleaseArgs{Dist: repo}
+		rh.upstream = merged.LoadUpstream
+		rh.pool = merged
+		rh.policy = merged.policy
+		filtered = merged.LoadFiltered
+		if debCfg.IncludeDependencies {
+			filtered = withDependencies(filtered, merged.LoadUpstream)
+		}
+		filtered = merged.resolvingConflicts(filtered)
+	}
+	if retention := debCfg.Retention; retention != nil {
+		if retention.Versions <= 0 && retention.Period <= 0 {
+			return nil, fmt.Errorf("retention of %s requires versions or a period", repo)
+		}
+		pin := func(args LoadPackagesArgs, pkgs []*hedge.DebianPackage) {
+			l.pin(rh, args, pkgs)
+		}
+		filtered = l.retention.retaining(repo, *retention, rh.policy, pin, filtered)
+	}
+
+	rh.packages = cached.Wrap(cached.WithPrefix[string, []byte](fmt.Sprintf("debian_filtered_packages:%s:%x", repo, rh.configDigest), l.cache), servedFromPool(repo, filtered), cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianPackages]())
+	return rh, nil
+}
+
+// componentPolicy returns the predicate of the policies that apply to a component.
+type componentPolicy func(Component) filter.Predicate[*hedge.DebianPackage]
+
+func newComponentPolicy(policies map[string]string, defaultPolicy filter.Config, componentPolicies map[string]filter.Config) (componentPolicy, error) {
+	newPredicate := func(policy filter.Config) (filter.Predicate[*hedge.DebianPackage], error) {
+		pred, err := filter.CuePoliciesToPredicate[*hedge.DebianPackage](policies, policy)
 		if err != nil {
 			return nil, err
 		}
-		packages[arch] = pkgs.Packages
+		if len(policy.Versions) > 0 {
+			versions, err := filter.MatchesVersions[*hedge.DebianPackage](policy.Versions, CompareVersions)
+			if err != nil {
+				return nil, err
+			}
+			pred = filter.AllOf(pred, versions)
+		}
+		return pred, nil
 	}
 
-	// Write the signed InRelease file:
-	ctx, span := h.tracer.Start(ctx, "debian.clearSign")
-	defer span.End()
-	var buf bytes.Buffer
-	enc, err := clearsign.Encode(&buf, rh.pk, nil)
+	defaultPredicate, err := newPredicate(defaultPolicy)
 	if err != nil {
 		return nil, err
 	}
+	componentPredicates := make(map[Component]filter.Predicate[*hedge.DebianPackage], len(componentPolicies))
+	for c, policy := range componentPolicies {
+		pred, err := newPredicate(policy)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", c, err)
+		}
+		componentPredicates[Component(c)] = pred
+	}
+	return func(component Component) filter.Predicate[*hedge.DebianPackage] {
+		if pred, ok := componentPredicates[component]; ok {
+			return pred
+		}
+		return defaultPredicate
+	}, nil
+}
 
-	if err := WriteReleaseFile(ctx, release, packages, enc); err != nil {
-		return nil, err
+// policyFiltered applies each component's policies to the upstream packages.
+func policyFiltered(tracer trace.Tracer, upstream cached.Function[LoadPackagesArgs, *hedge.DebianPackages], policy componentPolicy) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		return NewFilteredPackagesLoader(tracer, upstream, policy(args.Component)).LoadPackages(ctx, args)
+	}
+}
+
+// sourceLoaders create sources, sharing loaders and storage between them.
+type sourceLoaders struct {
+	tracer         trace.Tracer
+	client         *http.Client
+	cache          cached.ByteStorage
+	cachedFetch    cached.Function[string, []byte]
+	remoteReleases cached.Function[LoadReleaseArgs, *hedge.DebianRelease]
+	remotePackages cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	remoteSources  cached.Function[LoadSourcesArgs, *hedge.DebianSources]
+	remotePool     PoolLoader
+	retention      *retentionStore
+	// pin pins the pool files of retained versions in the background.
+	pin func(rh *repositoryHandler, args LoadPackagesArgs, pkgs []*hedge.DebianPackage)
+
+	remoteContents     cached.Function[LoadPackagesArgs, *hedge.DebianContents]
+	remoteTranslations cached.Function[LoadTranslationsArgs, *hedge.DebianTranslations]
+}
+
+// newSource configures a source. id must be unique, it is used to store the source's packages.
+func (l sourceLoaders) newSource(id string, cfg SourceConfig) (*source, error) {
+	switch {
+	case cfg.Upstream != nil:
+		return &source{
+			release: l.remoteReleases,
+			releaseArgs: LoadReleaseArgs{
+				MirrorURLs:    cfg.Upstream.MirrorURLs(),
+				Dist:          cfg.Upstream.Release,
+				Architectures: cfg.Upstream.Architectures,
+				Components:    cfg.Upstream.Components,
+				SigningKey:    cfg.Upstream.Key,
+			},
+			upstream:     withArchitectureAll(l.remotePackages),
+			sources:      l.remoteSources,
+			contents:     contentsWithArchitectureAll(l.remoteContents),
+			translations: l.remoteTranslations,
+			pool:         l.remotePool,
+		}, nil
+
+	case cfg.GitHub != nil:
+		gh, err := NewGitHubRepository(l.tracer, l.client, l.cachedFetch, *cfg.GitHub)
+		if err != nil {
+			return nil, fmt.Errorf("configuring GitHub source: %w", err)
+		}
+		return &source{
+			release:     observability.TracedFunc(l.tracer, "debian.LoadRelease", cached.Wrap(cached.WithPrefix[string, []byte]("debian_github_releases", l.cache), gh.LoadRelease, cached.AsProtoBuf[LoadReleaseArgs, *hedge.DebianRelease]())),
+			releaseArgs: LoadReleaseArgs{Dist: id},
+			upstream:    observability.TracedFunc(l.tracer, "debian.LoadPackages", cached.Wrap(cached.WithPrefix[string, []byte](fmt.Sprintf("debian_github_packages:%s", id), l.cache), gh.LoadPackages, cached.AsProtoBuf[LoadPackagesArgs, *hedge.DebianPackages]())),
+			pool:        gh,
+		}, nil
+
+	case cfg.Hosted != nil:
+		hosted, err := NewHostedRepository(l.tracer, l.cache, id, *cfg.Hosted)
+		if err != nil {
+			return nil, fmt.Errorf("configuring hosted source: %w", err)
+		}
+		return &source{
+			release:     hosted.LoadRelease,
+			releaseArgs: LoadReleaseArgs{Dist: id},
+			upstream:    hosted.LoadPackages,
+			pool:        hosted,
+			hosted:      hosted,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("no source configured")
 	}
-	if err := enc.Close(); err != nil {
+}
+
+func (h Handler) HandleInRelease(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, err := h.repository(ctx, req)
+	if err != nil {
 		return nil, err
 	}
-	if _, err = fmt.Fprintln(&buf); err != nil {
+	if rh == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	release, err := h.releaseFile(ctx, rh)
+	if err != nil {
 		return nil, err
 	}
 
+	// Write the signed InRelease file:
+	signed, err := h.signed(ctx, rh, "clearSign", release, rh.clearSign)
+	if err != nil {
+		return nil, err
+	}
 	return &hedge.HttpResponse{
-		Body: buf.Bytes(),
+		Body: signed,
 	}, nil
 }
 
-func (h Handler) HandlePackages(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
-	rh, ok := h.repos[req.PathVars["repository"]]
-	if !ok {
+// HandleRelease serves the unsigned Release file, for clients that verify Release.gpg instead of InRelease.
+func (h Handler) HandleRelease(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, err := h.repository(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rh == nil {
 		return &hedge.HttpResponse{
 			StatusCode: http.StatusNotFound,
 		}, nil
 	}
-	arch := Architecture(req.PathVars["arch"])
-	compression := CompressionFromExtension(req.PathVars["compression"])
-
-	// Load the release metadata:
-	release, err := h.releaseLoader(ctx, rh.releaseArgs)
+	release, err := h.releaseFile(ctx, rh)
 	if err != nil {
 		return nil, err
 	}
-	if release == nil {
-		return nil, fmt.Errorf("remote release not found")
-	}
+	return &hedge.HttpResponse{
+		Body: release,
+	}, nil
+}
 
-	// Load and serve the packages list. The client expects this to match what HandleInRelease digested
-	pkgs, err := h.packagesLoader(ctx, LoadPackagesArgs{
-		Release:      release,
-		Architecture: arch,
-	})
+// HandleReleaseSignature serves the armored detached signature of the Release file.
+func (h Handler) HandleReleaseSignature(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, err := h.repository(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-
-	graphs := make([]Paragraph, 0, len(pkgs.Packages))
-	for _, pkg := range pkgs.Packages {
-		graphs = append(graphs, ParagraphFromPackage(pkg))
+	if rh == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
 	}
-
-	var buf bytes.Buffer
-	if err := WriteControlFile(&buf, graphs...); err != nil {
+	release, err := h.releaseFile(ctx, rh)
+	if err != nil {
 		return nil, err
 	}
 
-	var compressed bytes.Buffer
-	if err := compression.Compress(&compressed, &buf); err != nil {
+	signature, err := h.signed(ctx, rh, "detachSign", release, rh.detachSign)
+	if err != nil {
 		return nil, err
 	}
 	return &hedge.HttpResponse{
-		Body: compressed.Bytes(),
+		Body: signature,
 	}, nil
 }
 
-// func (h *Handler) HandlePool(w http.ResponseWriter, r *http.Request) {
-// 	h.RepositoryHandler(w, r, "debian.HandlePool", func(ctx context.Context, vars map[string]string, rh *repositoryHandler) error {
-// 		path := vars["path"]
-// 		url := rh.packages.BaseURL() + path
-// 		r = r.WithContext(ctx)
-// 		http.Redirect(w, r, url, http.StatusMovedPermanently)
-// 		return nil
-// 	})
-// }
+// HandlePackages serves a Packages file, from the rendering that HandleInRelease digested.
+func (h Handler) HandlePackages(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	fn := fmt.Sprintf("%s/binary-%s/Packages", req.PathVars["component"], req.PathVars["arch"])
+	return h.serveRendered(ctx, req, fn, CompressionFromExtension(req.PathVars["compression"]))
+}
+
+// HandleByHash serves the variant of a Packages file matching a digest from the Release file.
+func (h Handler) HandleByHash(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	return h.serveRenderedByHash(ctx, req, fmt.Sprintf("%s/binary-%s", req.PathVars["component"], req.PathVars["arch"]))
+}
 
-func readKey(cfg *RepositoryConfig) (openpgp.EntityList, error) {
-	if cfg.KeyPath == "" {
-		return nil, fmt.Errorf("missing key")
+// repository resolves the repository of a request, or the snapshot of it in the request's path.
+// Returns nil if neither is found.
+func (h Handler) repository(ctx context.Context, req base.HttpRequest) (*repositoryHandler, error) {
+	rh := h.suite(req.PathVars["repository"], req.PathVars, "suite")
+	if rh == nil {
+		return nil, nil
+	}
+	timestamp, ok := req.PathVars["snapshot"]
+	if !ok {
+		return rh, nil
+	}
+	// Snapshots are served from their own dists, which may use the suite's alias:
+	if h.suite(req.PathVars["dist"], req.PathVars, "distSuite") != rh {
+		return nil, nil
 	}
-	keyIn, err := os.Open(cfg.KeyPath)
+	snapshot, err := h.snapshots.Lookup(ctx, rh.name, timestamp)
 	if err != nil {
 		return nil, err
 	}
-	defer keyIn.Close()
-	key, err := openpgp.ReadArmoredKeyRing(keyIn)
-	if err != nil {
-		return nil, fmt.Errorf("decoding key: %w", err)
+	if snapshot == nil {
+		return nil, nil
+	}
+	return rh.atSnapshot(snapshot), nil
+}
+
+// suite resolves a repository, and its suite if the repository has suites. Returns nil if either is not found.
+func (h Handler) suite(repo string, vars map[string]string, suiteVar string) *repositoryHandler {
+	rh, ok := h.repos[repo]
+	if !ok {
+		return nil
+	}
+	suite, ok := vars[suiteVar]
+	if !ok {
+		if rh.suites != nil {
+			return nil
+		}
+		return rh
+	}
+	return rh.suites[suite]
+}
+
+func (h Handler) loadRelease(ctx context.Context, rh *repositoryHandler) (*hedge.DebianRelease, error) {
+	// Snapshots are served as they were recorded:
+	if rh.snapshot != nil {
+		return rh.snapshot.Release, nil
+	}
+	return rh.liveRelease(ctx)
+}
+
+func writePackagesFile(w io.Writer, packages []*hedge.DebianPackage) error {
+	graphs := make([]Paragraph, 0, len(packages))
+	for _, pkg := range packages {
+		graphs = append(graphs, ParagraphFromPackage(pkg))
+	}
+	return WriteControlFile(w, graphs...)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
 	}
-	return key, nil
+	return false
 }
@@ -1,75 +1,773 @@
 package debian_test
 
-// import (
-// 	"bytes"
-// 	"io"
-// 	"io/ioutil"
-// 	"net/http"
-// 	"net/http/httptest"
-// 	"os"
-// 	"testing"
-
-// 	"github.com/ProtonMail/go-crypto/openpgp"
-// 	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
-// 	"github.com/gorilla/mux"
-// 	"github.com/stretchr/testify/assert"
-// 	"github.com/stretchr/testify/require"
-// 	"github.com/thepwagner/hedge/pkg/observability"
-// 	"github.com/thepwagner/hedge/pkg/registry/debian"
-// )
-
-// func TestDebianHandler(t *testing.T) {
-// 	// Mock bullseye mirror using test artifacts
-// 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-// 		var fn string
-// 		switch r.URL.Path {
-// 		case "/dists/bullseye/InRelease":
-// 			fn = "testdata/bullseye_InRelease"
-// 		case "/dists/bullseye/contrib/binary-amd64/Packages.gz":
-// 			fn = "testdata/bullseye_Packages.gz"
-// 		default:
-// 			assert.Fail(t, "unexpected path", r.URL.Path)
-// 		}
-// 		f, err := os.Open(fn)
-// 		require.NoError(t, err)
-// 		defer f.Close()
-// 		_, err = io.Copy(w, f)
-// 		require.NoError(t, err)
-// 	}))
-// 	defer srv.Close()
-
-// 	// Handler that sources mock mirror:
-// 	pubKey, err := ioutil.ReadFile("testdata/bullseye_pubkey.txt")
-// 	require.NoError(t, err)
-// 	h, err := debian.NewHandler(observability.NoopTracer, &http.Client{}, "testdata/config", map[string]*debian.RepositoryConfig{
-// 		"bullseye": {
-// 			KeyPath: "testdata/privkey.txt",
-// 			Source: debian.SourceConfig{
-// 				Upstream: &debian.UpstreamConfig{
-// 					URL:           srv.URL,
-// 					Release:       "bullseye",
-// 					Architectures: []string{"amd64"},
-// 					Components:    []string{"contrib"},
-// 					Key:           string(pubKey),
-// 				},
-// 			},
-// 		},
-// 	})
-// 	require.NoError(t, err)
-
-// 	// Request the signed release file:
-// 	req, _ := http.NewRequest("GET", "/debian/dists/bullseye/InRelease", nil)
-// 	req = mux.SetURLVars(req, map[string]string{"dist": "bullseye"})
-// 	resp := httptest.NewRecorder()
-// 	h.HandleInRelease(resp, req)
-// 	t.Log(resp.Body.String())
-
-// 	// Verify signature:
-// 	block, rest := clearsign.Decode(resp.Body.Bytes())
-// 	require.NotNil(t, block)
-// 	assert.Empty(t, rest)
-// 	kr, err := debian.ReadArmoredKeyRingFile("testdata/privkey.txt")
-// 	require.NoError(t, err)
-// 	_, err = openpgp.CheckDetachedSignature(kr, bytes.NewReader(block.Bytes), block.ArmoredSignature.Body, nil)
-// 	require.NoError(t, err)
-// }
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+const (
+	testPrivateKey = "testdata/privkey.txt"
+	testDebPath    = "pool/main/t/testpkg/testpkg_1.2.3_amd64.deb"
+	testSourcePath = "pool/main/t/testpkg/testpkg_1.2.3.dsc"
+)
+
+// testMirror serves a signed "test" dist. The "main" component contains testpkg, "contrib" is the contrib Packages from bullseye.
+type testMirror struct {
+	*httptest.Server
+	PubKey string
+
+	key      *openpgp.Entity
+	mu       sync.Mutex
+	files    map[string][]byte
+	packages map[string][]byte
+	sources  map[string][]byte
+	// allPackages are published as binary-all Packages files, if set.
+	allPackages map[string][]byte
+	// noSupportAll declares that the binary-amd64 Packages files include the `all` packages.
+	noSupportAll bool
+	// indices are other uncompressed index files, like Contents, by path relative to the dist.
+	indices map[string][]byte
+	// date and validUntil are published in the InRelease, validUntil is omitted if zero.
+	date       time.Time
+	validUntil time.Time
+}
+
+func newTestMirror(t *testing.T) *testMirror {
+	t.Helper()
+	ctx := context.Background()
+
+	contribGz, err := os.ReadFile("testdata/bullseye_Packages.gz")
+	require.NoError(t, err)
+	var contrib bytes.Buffer
+	err = debian.CompressionGZIP.Decompress(&contrib, bytes.NewReader(contribGz))
+	require.NoError(t, err)
+
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	testPkg, err := debian.NewParser(observability.NoopTracer).PackageFromDeb(ctx, bytes.NewReader(deb))
+	require.NoError(t, err)
+	testPkg.Filename = testDebPath
+	testPkg.Size = uint64(len(deb))
+	debDigest := sha256.Sum256(deb)
+	testPkg.Sha256 = debDigest[:]
+	var main bytes.Buffer
+	err = debian.WriteControlFile(&main, debian.ParagraphFromPackage(testPkg))
+	require.NoError(t, err)
+
+	keys := readTestKey(t)
+	var pubKey bytes.Buffer
+	w, err := armor.Encode(&pubKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, keys[0].Serialize(w))
+	require.NoError(t, w.Close())
+
+	// "main" also has sources: testpkg built the binary package, othersrc did not.
+	srcFiles := map[string][]byte{}
+	var sources bytes.Buffer
+	for _, src := range []*hedge.DebianSource{
+		{Name: "testpkg", Version: "1.2.3", Binaries: []string{"testpkg"}, Directory: path.Dir(testSourcePath), Files: []*hedge.DebianSource_File{{Name: "testpkg_1.2.3.dsc"}, {Name: "testpkg_1.2.3.orig.tar.gz"}}},
+		{Name: "othersrc", Version: "2.0", Binaries: []string{"other"}, Directory: "pool/main/o/othersrc", Files: []*hedge.DebianSource_File{{Name: "othersrc_2.0.dsc"}}},
+	} {
+		for _, f := range src.Files {
+			b := []byte(fmt.Sprintf("%s contents\n", f.Name))
+			digest := sha256.Sum256(b)
+			md := md5.Sum(b)
+			f.Size, f.Sha256, f.Md5Sum = uint64(len(b)), digest[:], md[:]
+			srcFiles["/"+path.Join(src.Directory, f.Name)] = b
+		}
+		if sources.Len() > 0 {
+			sources.WriteString("\n")
+		}
+		require.NoError(t, debian.WriteControlFile(&sources, debian.ParagraphFromSource(src)))
+	}
+
+	m := &testMirror{
+		PubKey: pubKey.String(),
+		key:    keys[0],
+		files: map[string][]byte{
+			"/" + testDebPath: deb,
+		},
+		sources: map[string][]byte{
+			"main": sources.Bytes(),
+		},
+		indices: map[string][]byte{
+			"main/Contents-amd64": []byte("usr/bin/testpkg                                             utils/testpkg\n" +
+				"usr/share/doc/shared/README                                 utils/testpkg,utils/other\n" +
+				"usr/bin/other                                               utils/other\n"),
+			"main/i18n/Translation-en": []byte("Package: testpkg\nDescription-md5: 0123456789abcdef0123456789abcdef\nDescription-en: test package\n a longer description\n .\n with a blank line\n\n" +
+				"Package: other\nDescription-md5: fedcba9876543210fedcba9876543210\nDescription-en: other package\n"),
+		},
+		packages: map[string][]byte{
+			"main":    main.Bytes(),
+			"contrib": contrib.Bytes(),
+		},
+		date: time.Date(2022, time.July, 9, 9, 43, 23, 0, time.UTC),
+	}
+	for fn, b := range srcFiles {
+		m.files[fn] = b
+	}
+	m.publish(t)
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		b, ok := m.files[r.URL.Path]
+		m.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// SetPackages replaces the uncompressed Packages file of a component, and publishes a new InRelease.
+func (m *testMirror) SetPackages(t *testing.T, component string, packages []byte) {
+	t.Helper()
+	m.mu.Lock()
+	m.packages[component] = packages
+	m.mu.Unlock()
+	m.publish(t)
+}
+
+// SetAllPackages publishes binary-all Packages files, with `all` in the InRelease Architectures.
+func (m *testMirror) SetAllPackages(t *testing.T, allPackages map[string][]byte, noSupportAll bool) {
+	t.Helper()
+	m.mu.Lock()
+	m.allPackages = allPackages
+	m.noSupportAll = noSupportAll
+	m.mu.Unlock()
+	m.publish(t)
+}
+
+// SetDates replaces the Date and Valid-Until of the InRelease, and publishes it.
+func (m *testMirror) SetDates(t *testing.T, date, validUntil time.Time) {
+	t.Helper()
+	m.mu.Lock()
+	m.date = date
+	m.validUntil = validUntil
+	m.mu.Unlock()
+	m.publish(t)
+}
+
+func (m *testMirror) publish(t *testing.T) {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	release := []string{
+		"Origin: Test",
+		"Label: Test",
+		"Suite: stable",
+		"Codename: test",
+		"Date: " + m.date.Format(time.RFC1123),
+		"Acquire-By-Hash: yes",
+		"Components: main contrib",
+		"Description: Test mirror",
+	}
+	if len(m.allPackages) > 0 {
+		release = append(release, "Architectures: all amd64")
+	} else {
+		release = append(release, "Architectures: amd64")
+	}
+	if m.noSupportAll {
+		release = append(release, "No-Support-for-Architecture-all: Packages")
+	}
+	if !m.validUntil.IsZero() {
+		release = append(release, "Valid-Until: "+m.validUntil.Format(time.RFC1123))
+	}
+	release = append(release, "SHA256:")
+	for _, component := range []string{"contrib", "main"} {
+		packages := m.packages[component]
+		var packagesGz bytes.Buffer
+		err := debian.CompressionGZIP.Compress(&packagesGz, bytes.NewReader(packages))
+		require.NoError(t, err)
+		gzDigest := sha256.Sum256(packagesGz.Bytes())
+		release = append(release,
+			fmt.Sprintf(" %x %d %s/binary-amd64/Packages", sha256.Sum256(packages), len(packages), component),
+			fmt.Sprintf(" %x %d %s/binary-amd64/Packages.gz", gzDigest, packagesGz.Len(), component),
+		)
+		m.files[fmt.Sprintf("/dists/test/%s/binary-amd64/by-hash/SHA256/%x", component, gzDigest)] = packagesGz.Bytes()
+
+		if allPackages, ok := m.allPackages[component]; ok {
+			var allGz bytes.Buffer
+			require.NoError(t, debian.CompressionGZIP.Compress(&allGz, bytes.NewReader(allPackages)))
+			gzDigest := sha256.Sum256(allGz.Bytes())
+			release = append(release, fmt.Sprintf(" %x %d %s/binary-all/Packages.gz", gzDigest, allGz.Len(), component))
+			m.files[fmt.Sprintf("/dists/test/%s/binary-all/by-hash/SHA256/%x", component, gzDigest)] = allGz.Bytes()
+		}
+
+		sources, ok := m.sources[component]
+		if !ok {
+			continue
+		}
+		var sourcesGz bytes.Buffer
+		err = debian.CompressionGZIP.Compress(&sourcesGz, bytes.NewReader(sources))
+		require.NoError(t, err)
+		gzDigest = sha256.Sum256(sourcesGz.Bytes())
+		release = append(release, fmt.Sprintf(" %x %d %s/source/Sources.gz", gzDigest, sourcesGz.Len(), component))
+		m.files[fmt.Sprintf("/dists/test/%s/source/by-hash/SHA256/%x", component, gzDigest)] = sourcesGz.Bytes()
+	}
+	for _, fn := range []string{"main/Contents-amd64", "main/i18n/Translation-en"} {
+		index, ok := m.indices[fn]
+		if !ok {
+			continue
+		}
+		var indexGz bytes.Buffer
+		require.NoError(t, debian.CompressionGZIP.Compress(&indexGz, bytes.NewReader(index)))
+		gzDigest := sha256.Sum256(indexGz.Bytes())
+		release = append(release, fmt.Sprintf(" %x %d %s.gz", gzDigest, indexGz.Len(), fn))
+		m.files[fmt.Sprintf("/dists/test/%s/by-hash/SHA256/%x", path.Dir(fn), gzDigest)] = indexGz.Bytes()
+	}
+	release = append(release, "")
+
+	var inRelease bytes.Buffer
+	enc, err := clearsign.Encode(&inRelease, m.key.PrivateKey, nil)
+	require.NoError(t, err)
+	_, err = enc.Write([]byte(strings.Join(release, "\n")))
+	require.NoError(t, err)
+	require.NoError(t, enc.Close())
+	m.files["/dists/test/InRelease"] = inRelease.Bytes()
+}
+
+func (m *testMirror) SetFile(path string, b []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[path] = b
+}
+
+func readTestKey(t *testing.T) openpgp.EntityList {
+	t.Helper()
+	f, err := os.Open(testPrivateKey)
+	require.NoError(t, err)
+	defer f.Close()
+	keys, err := openpgp.ReadArmoredKeyRing(f)
+	require.NoError(t, err)
+	return keys
+}
+
+func newTestHandler(t *testing.T, repoCfg *debian.RepositoryConfig, policies map[string]string) *base.CachedMux {
+	t.Helper()
+	return newTestHandlerWithStorage(t, cached.InMemory[string, []byte](), repoCfg, policies)
+}
+
+func newTestHandlerWithStorage(t *testing.T, storage cached.ByteStorage, repoCfg *debian.RepositoryConfig, policies map[string]string) *base.CachedMux {
+	t.Helper()
+	mux := base.NewCachedMux(observability.NoopTracer, storage)
+	_, err := debian.NewHandler(mux, observability.NoopTracer, storage, &http.Client{}, registry.EcosystemConfig{
+		Repositories: map[string]registry.RepositoryConfig{"test": repoCfg},
+		Policies:     policies,
+	})
+	require.NoError(t, err)
+	return mux
+}
+
+func testRepositoryConfig(mirrorURL, pubKey string, policies ...string) *debian.RepositoryConfig {
+	return &debian.RepositoryConfig{
+		KeyPath: testPrivateKey,
+		Source: debian.SourceConfig{
+			Upstream: &debian.UpstreamConfig{
+				URL:           mirrorURL,
+				Key:           pubKey,
+				Release:       "test",
+				Architectures: []string{"amd64"},
+			},
+		},
+		Policies: filter.Config{AnyOf: policies},
+	}
+}
+
+func get(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", path, nil))
+	return res
+}
+
+func getRelease(t *testing.T, h http.Handler, path string) *hedge.DebianRelease {
+	t.Helper()
+	res := get(t, h, path)
+	require.Equal(t, http.StatusOK, res.Code)
+	release, err := debian.NewParser(observability.NoopTracer).Release(context.Background(), res.Body, readTestKey(t))
+	require.NoError(t, err)
+	return release
+}
+
+func TestHandler_Filtered(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "alien-arena.cue"), map[string]string{
+		"alien-arena.cue": `name: =~"^alien-arena"`,
+	})
+
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+	assert.Equal(t, []string{"main", "contrib"}, release.Components)
+	digest, ok := release.Digests["contrib/binary-amd64/Packages"]
+	require.True(t, ok)
+	assert.Contains(t, release.Digests, "main/binary-amd64/Packages")
+
+	res := get(t, h, "/debian/dists/test/contrib/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	body := res.Body.Bytes()
+	assert.Equal(t, digest.Size, uint64(len(body)))
+	actualDigest := sha256.Sum256(body)
+	assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+
+	pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), bytes.NewReader(body))
+	require.NoError(t, err)
+	names := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		names = append(names, pkg.Name)
+	}
+	assert.ElementsMatch(t, []string{"alien-arena", "alien-arena-server"}, names)
+}
+
+func TestHandler_NoPolicies(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey), nil)
+
+	for _, component := range []string{"main", "contrib"} {
+		res := get(t, h, fmt.Sprintf("/debian/dists/test/%s/binary-amd64/Packages", component))
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Body.String())
+	}
+}
+
+func TestHandler_ComponentPolicies(t *testing.T) {
+	mirror := newTestMirror(t)
+	repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "everything.cue")
+	repoCfg.ComponentPolicies = map[string]filter.Config{
+		"contrib": {AnyOf: []string{"alien-arena.cue"}},
+	}
+	h := newTestHandler(t, repoCfg, map[string]string{
+		"everything.cue":  `name: string`,
+		"alien-arena.cue": `name: "alien-arena"`,
+	})
+
+	packageNames := func(component string) []string {
+		res := get(t, h, fmt.Sprintf("/debian/dists/test/%s/binary-amd64/Packages.gz", component))
+		require.Equal(t, http.StatusOK, res.Code)
+		var buf bytes.Buffer
+		require.NoError(t, debian.CompressionGZIP.Decompress(&buf, res.Body))
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), &buf)
+		require.NoError(t, err)
+		names := make([]string, 0, len(pkgs))
+		for _, pkg := range pkgs {
+			names = append(names, pkg.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"testpkg"}, packageNames("main"))
+	assert.Equal(t, []string{"alien-arena"}, packageNames("contrib"))
+
+	t.Run("unknown component", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/non-free/binary-amd64/Packages")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("unknown architecture", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/main/binary-arm64/Packages")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestHandler_Pool(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+	})
+
+	// Packages are served from the repository's pool:
+	res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+	require.NoError(t, err)
+	require.Len(t, pkgs, 1)
+	assert.Equal(t, "dists/test/"+testDebPath, pkgs[0].Filename)
+
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	res = get(t, h, "/debian/"+pkgs[0].Filename)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/vnd.debian.binary-package", res.Header().Get("Content-Type"))
+	assert.Equal(t, deb, res.Body.Bytes())
+
+	t.Run("cached", func(t *testing.T) {
+		mirror.SetFile("/"+testDebPath, nil)
+		res := get(t, h, "/debian/"+pkgs[0].Filename)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, deb, res.Body.Bytes())
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/pool/contrib/a/alien-arena/alien-arena_7.66+dfsg-6_amd64.deb")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/pool/main/n/nope/nope_1.0_amd64.deb")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestHandler_PoolVerified(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+	})
+	mirror.SetFile("/"+testDebPath, []byte("tampered"))
+
+	res := get(t, h, "/debian/dists/test/"+testDebPath)
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+	assert.Empty(t, res.Body.Bytes())
+}
+
+func TestHandler_Release(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+	})
+
+	res := get(t, h, "/debian/dists/test/Release")
+	require.Equal(t, http.StatusOK, res.Code)
+	release := res.Body.Bytes()
+	assert.Contains(t, string(release), "Acquire-By-Hash: yes\n")
+
+	// InRelease signs the same content:
+	res = get(t, h, "/debian/dists/test/InRelease")
+	require.Equal(t, http.StatusOK, res.Code)
+	block, _ := clearsign.Decode(res.Body.Bytes())
+	require.NotNil(t, block)
+	assert.Equal(t, string(release), string(block.Plaintext))
+
+	res = get(t, h, "/debian/dists/test/Release.gpg")
+	require.Equal(t, http.StatusOK, res.Code)
+	_, err := openpgp.CheckArmoredDetachedSignature(readTestKey(t), bytes.NewReader(release), res.Body, nil)
+	assert.NoError(t, err)
+}
+
+func TestHandler_ByHash(t *testing.T) {
+	mirror := newTestMirror(t)
+	h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+	})
+
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+	for _, fn := range []string{"main/binary-amd64/Packages", "main/binary-amd64/Packages.gz"} {
+		digest, ok := release.Digests[fn]
+		require.True(t, ok, fn)
+		assert.Equal(t, fmt.Sprintf("main/binary-amd64/by-hash/SHA256/%x", digest.Sha256Sum), digest.Path)
+
+		res := get(t, h, "/debian/dists/test/"+digest.Path)
+		require.Equal(t, http.StatusOK, res.Code, fn)
+		actualDigest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, digest.Sha256Sum, actualDigest[:], fn)
+
+		plain := get(t, h, "/debian/dists/test/"+fn)
+		assert.Equal(t, plain.Body.Bytes(), res.Body.Bytes(), fn)
+	}
+
+	t.Run("unknown digest", func(t *testing.T) {
+		res := get(t, h, fmt.Sprintf("/debian/dists/test/main/binary-amd64/by-hash/SHA256/%x", sha256.Sum256([]byte("nope"))))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("invalid digest", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/by-hash/SHA256/nope")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestHandler_IncludeDependencies(t *testing.T) {
+	mirror := newTestMirror(t)
+	repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "dhewm3-d3xp.cue")
+	repoCfg.IncludeDependencies = true
+	h := newTestHandler(t, repoCfg, map[string]string{
+		"dhewm3-d3xp.cue": `name: "dhewm3-d3xp"`,
+	})
+
+	res := get(t, h, "/debian/dists/test/contrib/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+	require.NoError(t, err)
+	names := make([]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		names = append(names, pkg.Name)
+	}
+	assert.ElementsMatch(t, []string{"dhewm3-d3xp", "dhewm3-doom3", "dhewm3"}, names)
+
+	// Other components only include dependencies:
+	res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Body.String())
+}
+
+func TestHandler_Versions(t *testing.T) {
+	cases := map[string][]string{
+		">= 7.66+dfsg-6":         {"alien-arena", "alien-arena-server"},
+		">> 7.66+dfsg-6":         nil,
+		">= 7.66~rc1, << 1:7.66": {"alien-arena", "alien-arena-server"},
+		"<< 7.66":                nil,
+	}
+	for constraint, expected := range cases {
+		t.Run(constraint, func(t *testing.T) {
+			mirror := newTestMirror(t)
+			repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "alien-arena.cue")
+			repoCfg.Policies.Versions = map[string]string{
+				"alien-arena":        constraint,
+				"alien-arena-server": constraint,
+			}
+			h := newTestHandler(t, repoCfg, map[string]string{
+				"alien-arena.cue": `name: =~"^alien-arena"`,
+			})
+
+			res := get(t, h, "/debian/dists/test/contrib/binary-amd64/Packages")
+			require.Equal(t, http.StatusOK, res.Code)
+			pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+			require.NoError(t, err)
+			var names []string
+			for _, pkg := range pkgs {
+				names = append(names, pkg.Name)
+			}
+			assert.ElementsMatch(t, expected, names)
+		})
+	}
+}
+
+func TestHandler_Mirrors(t *testing.T) {
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+
+	assertServes := func(t *testing.T, h http.Handler) {
+		t.Helper()
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Contains(t, release.Digests, "main/binary-amd64/Packages")
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), "Package: testpkg\n")
+		res = get(t, h, "/debian/dists/test/"+testDebPath)
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, deb, res.Body.Bytes())
+	}
+
+	t.Run("unavailable", func(t *testing.T) {
+		down := httptest.NewServer(http.NotFoundHandler())
+		down.Close()
+		mirror := newTestMirror(t)
+		repoCfg := testRepositoryConfig(down.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.Source.Upstream.Mirrors = []string{mirror.URL}
+		assertServes(t, newTestHandler(t, repoCfg, policies))
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		tampered := newTestMirror(t)
+		for path := range tampered.files {
+			tampered.SetFile(path, []byte("tampered"))
+		}
+		mirror := newTestMirror(t)
+		repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+		// The tampered mirror serves a valid release, but nothing else:
+		tampered.SetFile("/dists/test/InRelease", mirror.files["/dists/test/InRelease"])
+		repoCfg.Source.Upstream.URL = tampered.URL
+		repoCfg.Source.Upstream.Mirrors = []string{mirror.URL}
+		assertServes(t, newTestHandler(t, repoCfg, policies))
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		t.Cleanup(slow.Close)
+		mirror := newTestMirror(t)
+		repoCfg := testRepositoryConfig(slow.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.Source.Upstream.Mirrors = []string{mirror.URL}
+		h := newTestHandler(t, repoCfg, policies)
+		res := get(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("all down", func(t *testing.T) {
+		down := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(down.Close)
+		mirror := newTestMirror(t)
+		repoCfg := testRepositoryConfig(down.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.Source.Upstream.Mirrors = []string{down.URL + "/also-missing"}
+		res := get(t, newTestHandler(t, repoCfg, policies), "/debian/dists/test/InRelease")
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+	})
+}
+
+func TestHandler_Snapshots(t *testing.T) {
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	policies := map[string]string{"everything.cue": `name: string`}
+	storage := cached.InMemory[string, []byte]()
+
+	// Serve the repository, then serve it again after upstream removes testpkg and its pool file:
+	before := newTestMirror(t)
+	h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(before.URL, before.PubKey, "everything.cue"), policies)
+	getRelease(t, h, "/debian/dists/test/InRelease")
+	getRelease(t, h, "/debian/dists/test/InRelease")
+	waitPinned(t, storage, deb)
+	after := newTestMirror(t)
+	after.SetPackages(t, "main", nil)
+	after.SetFile("/"+testDebPath, nil)
+	h = newTestHandlerWithStorage(t, storage, testRepositoryConfig(after.URL, after.PubKey, "everything.cue"), policies)
+	getRelease(t, h, "/debian/dists/test/InRelease")
+
+	res := get(t, h, "/debian/snapshots/test")
+	require.Equal(t, http.StatusOK, res.Code)
+	ids := strings.Fields(res.Body.String())
+	require.Len(t, ids, 2)
+	assert.Less(t, ids[0], ids[1])
+
+	snapshotPackages := func(t *testing.T, snapshot string) string {
+		t.Helper()
+		release := getRelease(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/InRelease", snapshot))
+		digest, ok := release.Digests["main/binary-amd64/Packages"]
+		require.True(t, ok)
+		res := get(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/main/binary-amd64/Packages", snapshot))
+		require.Equal(t, http.StatusOK, res.Code)
+		actualDigest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+		return res.Body.String()
+	}
+
+	t.Run("before", func(t *testing.T) {
+		assert.Contains(t, snapshotPackages(t, ids[0]), "Package: testpkg\n")
+		res := get(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/%s", ids[0], testDebPath))
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, deb, res.Body.Bytes())
+	})
+
+	t.Run("after", func(t *testing.T) {
+		assert.Empty(t, snapshotPackages(t, ids[1]))
+		res := get(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/%s", ids[1], testDebPath))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("pinned to a date", func(t *testing.T) {
+		assert.Empty(t, snapshotPackages(t, "99991231T235959Z"))
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		// Like the live repository, files upstream has are refused, and other files are not found:
+		h := newTestHandler(t, testRepositoryConfig(before.URL, before.PubKey, "testpkg.cue"), map[string]string{"testpkg.cue": `name: "testpkg"`})
+		getRelease(t, h, "/debian/dists/test/InRelease")
+		res := get(t, h, "/debian/snapshots/test")
+		require.Equal(t, http.StatusOK, res.Code)
+		id := strings.TrimSpace(res.Body.String())
+		for _, prefix := range []string{"/debian/dists/test/", fmt.Sprintf("/debian/snapshots/test/%s/dists/test/", id)} {
+			res := get(t, h, prefix+"pool/contrib/a/alien-arena/alien-arena_7.66+dfsg-6_amd64.deb")
+			assert.Equal(t, http.StatusForbidden, res.Code, prefix)
+			res = get(t, h, prefix+"pool/main/n/nope/nope_1.0_amd64.deb")
+			assert.Equal(t, http.StatusNotFound, res.Code, prefix)
+		}
+	})
+
+	for _, path := range []string{
+		"/debian/snapshots/test/20000101T000000Z/dists/test/InRelease",
+		"/debian/snapshots/test/yesterday/dists/test/InRelease",
+		fmt.Sprintf("/debian/snapshots/test/%s/dists/other/InRelease", ids[0]),
+		fmt.Sprintf("/debian/snapshots/other/%s/dists/other/InRelease", ids[0]),
+		"/debian/snapshots/other",
+	} {
+		t.Run(path, func(t *testing.T) {
+			res := get(t, h, path)
+			assert.Equal(t, http.StatusNotFound, res.Code)
+		})
+	}
+}
+
+func TestHandler_PoolContents(t *testing.T) {
+	deb := buildDeb(t, debian.CompressionXZ)
+	pkg, err := debian.NewParser(observability.NoopTracer).PackageFromDeb(context.Background(), bytes.NewReader(deb))
+	require.NoError(t, err)
+	pkg.Filename = "pool/main/t/tool/tool_1.0_amd64.deb"
+	pkg.Size = uint64(len(deb))
+	digest := sha256.Sum256(deb)
+	pkg.Sha256 = digest[:]
+	// app depends on tool, so tool can be served as a dependency:
+	app := &hedge.DebianPackage{Name: "app", Version: "1.0", Architecture: "amd64", Filename: "pool/main/a/app/app_1.0_amd64.deb", Size: 1, Sha256: make([]byte, 32), Depends: []*hedge.DebianDependency{{Alternatives: []*hedge.DebianRelation{{Name: "tool"}}}}}
+	var packages bytes.Buffer
+	require.NoError(t, debian.WriteControlFile(&packages, debian.ParagraphFromPackage(pkg), debian.ParagraphFromPackage(app)))
+
+	mirror := newTestMirror(t)
+	mirror.SetPackages(t, "main", packages.Bytes())
+	mirror.SetFile("/"+pkg.Filename, deb)
+	postinstDigest := sha256.Sum256([]byte(testPostinst))
+	policies := map[string]string{
+		"tool.cue":       `name: "tool"`,
+		"app.cue":        `name: "app"`,
+		"no-scripts.cue": `name: "tool", maintainer_scripts: close({})`,
+		"pinned.cue":     fmt.Sprintf(`name: "tool", maintainer_scripts: postinst: sha256: "%x"`, postinstDigest),
+		"wrong.cue":      `name: "tool", maintainer_scripts: postinst: sha256: "00"`,
+		"pinned-any.cue": fmt.Sprintf(`maintainer_scripts: postinst: sha256: "%x"`, postinstDigest),
+		"wrong-any.cue":  `maintainer_scripts: postinst: sha256: "00"`,
+	}
+
+	cases := map[string]struct {
+		policy         string
+		contentPolicy  string
+		expected       int
+		includeDepends bool
+	}{
+		"allowed":                       {policy: "tool.cue", expected: http.StatusOK},
+		"no scripts":                    {policy: "no-scripts.cue", expected: http.StatusForbidden},
+		"pinned script":                 {policy: "pinned.cue", expected: http.StatusOK},
+		"wrong script":                  {policy: "wrong.cue", expected: http.StatusForbidden},
+		"content policy":                {policy: "tool.cue", contentPolicy: "pinned-any.cue", expected: http.StatusOK},
+		"refused by content":            {policy: "tool.cue", contentPolicy: "wrong-any.cue", expected: http.StatusForbidden},
+		"dependency":                    {policy: "app.cue", includeDepends: true, expected: http.StatusOK},
+		"dependency content":            {policy: "app.cue", contentPolicy: "pinned-any.cue", includeDepends: true, expected: http.StatusOK},
+		"dependency refused by content": {policy: "app.cue", contentPolicy: "wrong-any.cue", includeDepends: true, expected: http.StatusForbidden},
+	}
+	for label, tc := range cases {
+		t.Run(label, func(t *testing.T) {
+			repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, tc.policy)
+			repoCfg.IncludeDependencies = tc.includeDepends
+			if tc.contentPolicy != "" {
+				repoCfg.ContentPolicies = &filter.Config{AnyOf: []string{tc.contentPolicy}}
+			}
+			storage := cached.InMemory[string, []byte]()
+			h := newTestHandlerWithStorage(t, storage, repoCfg, policies)
+
+			// Content policies can only be applied once the package is fetched:
+			res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Contains(t, res.Body.String(), "Package: tool\n")
+
+			res = get(t, h, "/debian/dists/test/"+pkg.Filename)
+			assert.Equal(t, tc.expected, res.Code)
+
+			// Refused files are not stored:
+			stored, err := storage.Get(context.Background(), fmt.Sprintf("debian_blobs:%x", digest))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected == http.StatusOK, stored != nil)
+		})
+	}
+}
@@ -0,0 +1,411 @@
+package debian
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// hostedTTL is how long uploaded packages are stored. Hosted sources have no upstream, so this can be long.
+const hostedTTL = 10 * 365 * 24 * time.Hour
+
+const hostedIndexKey = "index"
+
+// defaultMaxUploadSize limits the size of uploaded packages, if HostedConfig.MaxUploadSize is unset.
+const defaultMaxUploadSize = 100 << 20
+
+// Reference: https://www.debian.org/doc/debian-policy/ch-controlfields.html#source
+var (
+	packageNameRE    = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+$`)
+	packageVersionRE = regexp.MustCompile(`^[A-Za-z0-9.+~:-]+$`)
+)
+
+var (
+	// errUploadConflict is returned when a different file was already uploaded as the same package.
+	errUploadConflict = errors.New("a different file was uploaded with the same name, version and architecture")
+	// errInvalidUpload is returned when an upload is not a package this source can serve.
+	errInvalidUpload = errors.New("invalid upload")
+)
+
+// HostedRepository serves .deb packages uploaded to hedge.
+type HostedRepository struct {
+	tracer  trace.Tracer
+	storage cached.ByteStorage
+	parser  Parser
+	now     func() time.Time
+
+	release *hedge.DebianRelease
+	tokens  [][]byte
+	// maxUploadSize limits the size of uploaded packages.
+	maxUploadSize int64
+
+	// mu serializes updates to the index within this process.
+	mu sync.Mutex
+}
+
+var (
+	_ ReleaseLoader  = (*HostedRepository)(nil)
+	_ PackagesLoader = (*HostedRepository)(nil)
+	_ PoolLoader     = (*HostedRepository)(nil)
+)
+
+// NewHostedRepository creates a hosted source. id must be unique, it is used to store the source's uploads.
+func NewHostedRepository(tracer trace.Tracer, storage cached.ByteStorage, id string, cfg HostedConfig) (*HostedRepository, error) {
+	release := cfg.Release
+	if release == nil {
+		release = &hedge.DebianRelease{}
+	}
+	if len(release.Architectures) == 0 {
+		return nil, fmt.Errorf("hosted release has no architectures")
+	}
+
+	tokens, err := readTokens(cfg.TokensPath)
+	if err != nil {
+		return nil, err
+	}
+	maxUploadSize := cfg.MaxUploadSize
+	if maxUploadSize < 0 {
+		return nil, fmt.Errorf("invalid maxUploadSize %d", maxUploadSize)
+	} else if maxUploadSize == 0 {
+		maxUploadSize = defaultMaxUploadSize
+	}
+	return &HostedRepository{
+		tracer:        tracer,
+		storage:       cached.WithPrefix[string, []byte](fmt.Sprintf("debian_hosted:%s", id), storage),
+		parser:        NewParser(tracer),
+		now:           time.Now,
+		release:       release,
+		tokens:        tokens,
+		maxUploadSize: maxUploadSize,
+	}, nil
+}
+
+// readTokens reads upload tokens from a file, one per line.
+func readTokens(fn string) ([][]byte, error) {
+	if fn == "" {
+		return nil, fmt.Errorf("hosted source has no tokensPath")
+	}
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, fmt.Errorf("opening tokens: %w", err)
+	}
+	defer f.Close()
+
+	var tokens [][]byte
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if token := strings.TrimSpace(scanner.Text()); token != "" {
+			tokens = append(tokens, []byte(token))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading tokens: %w", err)
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no tokens in %s", fn)
+	}
+	return tokens, nil
+}
+
+// Authorized reports whether a request has one of the source's tokens, as `Authorization: Bearer {token}`.
+func (h *HostedRepository) Authorized(header http.Header) bool {
+	scheme, token, ok := strings.Cut(header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return false
+	}
+	var match int
+	for _, t := range h.tokens {
+		match |= subtle.ConstantTimeCompare(t, []byte(token))
+	}
+	return match == 1
+}
+
+// LoadRelease synthesizes a release from the configured template. The release is dated by the latest upload,
+// so it changes whenever the packages do, and only then.
+func (h *HostedRepository) LoadRelease(ctx context.Context, args LoadReleaseArgs) (*hedge.DebianRelease, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.HostedRepository.LoadRelease", trace.WithAttributes(attrDist(args.Dist)))
+	defer span.End()
+
+	index, err := h.index(ctx)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	release := proto.Clone(h.release).(*hedge.DebianRelease)
+	release.Dist = args.Dist
+	// Without uploads, the date is fixed so the release doesn't change between requests:
+	release.Date = timestamppb.New(time.Unix(0, 0).UTC())
+	if index.Updated != nil {
+		release.Date = index.Updated
+	}
+	if release.Codename == "" {
+		release.Codename = args.Dist
+	}
+	if len(release.Components) == 0 {
+		release.Components = []string{"main"}
+	}
+	return release, nil
+}
+
+// LoadPackages returns the packages uploaded to a component and architecture, and the component's `all` packages.
+func (h *HostedRepository) LoadPackages(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.HostedRepository.LoadPackages", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
+	defer span.End()
+
+	index, err := h.index(ctx)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	var pkgs []*hedge.DebianPackage
+	for _, arch := range []Architecture{args.Architecture, ArchitectureAll} {
+		if uploaded, ok := index.Packages[snapshotPackagesKey(args.Component, arch)]; ok {
+			pkgs = append(pkgs, uploaded.Packages...)
+		}
+		if args.Architecture == ArchitectureAll {
+			break
+		}
+	}
+	span.SetAttributes(attrPackageCount(len(pkgs)))
+	return &hedge.DebianPackages{Packages: pkgs}, nil
+}
+
+func (h *HostedRepository) LoadPoolFile(ctx context.Context, args LoadPoolFileArgs) ([]byte, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.HostedRepository.LoadPoolFile", trace.WithAttributes(attrFilename(args.Filename)))
+	defer span.End()
+
+	b, err := h.storage.Get(ctx, hostedBlobKey(args.Sha256))
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if b == nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("uploaded file not found: %s", args.Filename))
+	}
+	if err := verifyFile(*b, args.Size, args.Sha256); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return *b, nil
+}
+
+// Parse reads an uploaded .deb into the package it would be served as.
+func (h *HostedRepository) Parse(ctx context.Context, component Component, deb []byte) (*hedge.DebianPackage, error) {
+	pkg, err := h.parser.PackageFromDeb(ctx, bytes.NewReader(deb))
+	if err != nil {
+		return nil, fmt.Errorf("%w: parsing package: %v", errInvalidUpload, err)
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("%w: control file not found", errInvalidUpload)
+	}
+	// Names and versions become the pool path, so they must not escape it:
+	if !packageNameRE.MatchString(pkg.Name) || !packageVersionRE.MatchString(pkg.Version) {
+		return nil, fmt.Errorf("%w: invalid name or version %q %q", errInvalidUpload, pkg.Name, pkg.Version)
+	}
+	if pkg.Architecture != string(ArchitectureAll) && !contains(h.release.Architectures, pkg.Architecture) {
+		return nil, fmt.Errorf("%w: architecture %q is not served", errInvalidUpload, pkg.Architecture)
+	}
+
+	pkg.Filename = hostedFilename(component, pkg)
+	pkg.Size = uint64(len(deb))
+	md := md5.Sum(deb)
+	pkg.Md5Sum = md[:]
+	sha := sha256.Sum256(deb)
+	pkg.Sha256 = sha[:]
+	return pkg, nil
+}
+
+// Upload stores a package parsed by Parse, and adds it to the index.
+// Uploading the same file again is allowed, replacing a package with a different file is not.
+func (h *HostedRepository) Upload(ctx context.Context, component Component, pkg *hedge.DebianPackage, deb []byte) error {
+	ctx, span := h.tracer.Start(ctx, "debian.HostedRepository.Upload", trace.WithAttributes(attrComponent(string(component)), attrFilename(pkg.Filename)))
+	defer span.End()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	index, err := h.index(ctx)
+	if err != nil {
+		return observability.CaptureError(span, err)
+	}
+	key := snapshotPackagesKey(component, Architecture(pkg.Architecture))
+	pkgs, ok := index.Packages[key]
+	if !ok {
+		pkgs = &hedge.DebianPackages{}
+	}
+	for _, existing := range pkgs.Packages {
+		if existing.Filename != pkg.Filename {
+			continue
+		}
+		if !bytes.Equal(existing.Sha256, pkg.Sha256) {
+			return observability.CaptureError(span, errUploadConflict)
+		}
+		return nil
+	}
+
+	// Store the file before it is indexed, so indexed files can always be served:
+	if err := h.storage.Set(ctx, hostedBlobKey(pkg.Sha256), deb, hostedTTL); err != nil {
+		return observability.CaptureError(span, err)
+	}
+	if index.Packages == nil {
+		index.Packages = map[string]*hedge.DebianPackages{}
+	}
+	pkgs.Packages = append(pkgs.Packages, pkg)
+	index.Packages[key] = pkgs
+	index.Updated = timestamppb.New(h.now().UTC())
+	b, err := proto.Marshal(index)
+	if err != nil {
+		return observability.CaptureError(span, err)
+	}
+	if err := h.storage.Set(ctx, hostedIndexKey, b, hostedTTL); err != nil {
+		return observability.CaptureError(span, err)
+	}
+	return nil
+}
+
+func (h *HostedRepository) index(ctx context.Context) (*hedge.DebianHostedPackages, error) {
+	var index hedge.DebianHostedPackages
+	b, err := h.storage.Get(ctx, hostedIndexKey)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return &index, nil
+	}
+	if err := proto.Unmarshal(*b, &index); err != nil {
+		return nil, fmt.Errorf("decoding hosted index: %w", err)
+	}
+	return &index, nil
+}
+
+func hostedBlobKey(digest []byte) string {
+	return "blob:" + hex.EncodeToString(digest)
+}
+
+// hostedFilename is the pool path of an uploaded package, following the layout of Debian's pool like
+// `pool/main/t/testpkg/testpkg_1.2.3_amd64.deb`. Epochs are not part of the filename.
+func hostedFilename(component Component, pkg *hedge.DebianPackage) string {
+	prefix := pkg.Name[:1]
+	if strings.HasPrefix(pkg.Name, "lib") && len(pkg.Name) > 3 {
+		prefix = pkg.Name[:4]
+	}
+	version := pkg.Version
+	if _, v, ok := strings.Cut(version, ":"); ok {
+		version = v
+	}
+	return path.Join("pool", string(component), prefix, pkg.Name, fmt.Sprintf("%s_%s_%s.deb", pkg.Name, version, pkg.Architecture))
+}
+
+// HandleUpload adds a .deb to a repository's hosted source. The component defaults to the release's first component.
+func (h Handler) HandleUpload(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, ok := h.repos[req.PathVars["repository"]]
+	if !ok || rh.hosted == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	if req.Method != http.MethodPut && req.Method != http.MethodPost {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusMethodNotAllowed,
+		}, nil
+	}
+	if !rh.hosted.Authorized(req.Header) {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusUnauthorized,
+		}, nil
+	}
+
+	release, err := rh.hosted.LoadRelease(ctx, LoadReleaseArgs{Dist: rh.name})
+	if err != nil {
+		return nil, err
+	}
+	component, ok := req.PathVars["component"]
+	if !ok {
+		component = release.Components[0]
+	}
+	if !contains(release.Components, component) {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+
+	// The body is only read once the upload is authorized, and never past the limit:
+	if req.Body == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusBadRequest,
+		}, nil
+	}
+	deb, err := io.ReadAll(io.LimitReader(req.Body, rh.hosted.maxUploadSize+1))
+	if err != nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusBadRequest,
+		}, nil
+	}
+	if int64(len(deb)) > rh.hosted.maxUploadSize {
+		return &hedge.HttpResponse{
+			StatusCode:  http.StatusRequestEntityTooLarge,
+			ContentType: "text/plain",
+			Body:        []byte(fmt.Sprintf("uploads are limited to %d bytes", rh.hosted.maxUploadSize)),
+		}, nil
+	}
+
+	pkg, err := rh.hosted.Parse(ctx, Component(component), deb)
+	if errors.Is(err, errInvalidUpload) {
+		return &hedge.HttpResponse{
+			StatusCode:  http.StatusBadRequest,
+			ContentType: "text/plain",
+			Body:        []byte(err.Error()),
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Uploads are subject to the same policies as mirrored packages, including their contents:
+	pred := rh.hostedPolicy(Component(component))
+	allowed, err := pred(ctx, pkg)
+	if err != nil {
+		return nil, err
+	}
+	if allowed {
+		if allowed, err = h.allowedContents(ctx, pred, rh.contentPolicy, pkg, true, loadedFile(deb)); err != nil {
+			return nil, err
+		}
+	}
+	if !allowed {
+		return &hedge.HttpResponse{
+			StatusCode:  http.StatusForbidden,
+			ContentType: "text/plain",
+			Body:        []byte(fmt.Sprintf("%s is not allowed by policy", pkg.Filename)),
+		}, nil
+	}
+
+	if err := rh.hosted.Upload(ctx, Component(component), pkg, deb); errors.Is(err, errUploadConflict) {
+		return &hedge.HttpResponse{
+			StatusCode:  http.StatusConflict,
+			ContentType: "text/plain",
+			Body:        []byte(err.Error()),
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &hedge.HttpResponse{
+		StatusCode: http.StatusCreated,
+	}, nil
+}
@@ -0,0 +1,172 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+const testUploadToken = "upload-token"
+
+func testHostedConfig(t *testing.T, policies ...string) *debian.RepositoryConfig {
+	t.Helper()
+	tokensPath := filepath.Join(t.TempDir(), "tokens")
+	require.NoError(t, os.WriteFile(tokensPath, []byte("other-token\n"+testUploadToken+"\n"), 0600))
+	return &debian.RepositoryConfig{
+		KeyPath: testPrivateKey,
+		Source: debian.SourceConfig{
+			Hosted: &debian.HostedConfig{
+				Release: &hedge.DebianRelease{
+					Architectures: []string{"amd64"},
+					Components:    []string{"main", "testing"},
+					Origin:        "Hosted",
+				},
+				TokensPath: tokensPath,
+			},
+		},
+		Policies: filter.Config{AnyOf: policies},
+	}
+}
+
+func upload(t *testing.T, h http.Handler, method, path, token string, body []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	return res
+}
+
+func TestHandler_Hosted(t *testing.T) {
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	policies := map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+		"nothing.cue": `name: "nothing"`,
+	}
+	packages := func(t *testing.T, h http.Handler, component string) []*hedge.DebianPackage {
+		t.Helper()
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, "Hosted", release.Origin)
+		res := get(t, h, "/debian/dists/test/"+component+"/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		return pkgs
+	}
+
+	t.Run("upload", func(t *testing.T) {
+		h := newTestHandler(t, testHostedConfig(t, "testpkg.cue"), policies)
+		assert.Empty(t, packages(t, h, "main"))
+		// Without uploads, the release has a fixed date so it isn't rendered again:
+		empty := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, time.Unix(0, 0).UTC(), empty.Date.AsTime())
+
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+
+		// The upload is served immediately, from the first component:
+		pkgs := packages(t, h, "main")
+		require.Len(t, pkgs, 1)
+		assert.Equal(t, "testpkg", pkgs[0].Name)
+		assert.Equal(t, "dists/test/pool/main/t/testpkg/testpkg_1.2.3_amd64.deb", pkgs[0].Filename)
+		assert.Empty(t, packages(t, h, "testing"))
+		res = get(t, h, "/debian/"+pkgs[0].Filename)
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, deb, res.Body.Bytes())
+
+		// Uploading the same file again is a no-op:
+		res = upload(t, h, http.MethodPost, "/debian/upload/test/main", testUploadToken, deb)
+		assert.Equal(t, http.StatusCreated, res.Code)
+		assert.Len(t, packages(t, h, "main"), 1)
+
+		// Replacing the file is not. The mtime of the first ar member changes the digest, not the package:
+		tampered := append([]byte(nil), deb...)
+		tampered[len("!<arch>\n")+16] ^= 1
+		res = upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, tampered)
+		assert.Equal(t, http.StatusConflict, res.Code)
+	})
+
+	t.Run("component", func(t *testing.T) {
+		h := newTestHandler(t, testHostedConfig(t, "testpkg.cue"), policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test/testing", testUploadToken, deb)
+		require.Equal(t, http.StatusCreated, res.Code)
+		pkgs := packages(t, h, "testing")
+		require.Len(t, pkgs, 1)
+		assert.Equal(t, "dists/test/pool/testing/t/testpkg/testpkg_1.2.3_amd64.deb", pkgs[0].Filename)
+
+		res = upload(t, h, http.MethodPut, "/debian/upload/test/contrib", testUploadToken, deb)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("uploads are stored", func(t *testing.T) {
+		storage := cached.InMemory[string, []byte]()
+		cfg := testHostedConfig(t, "testpkg.cue")
+		h := newTestHandlerWithStorage(t, storage, cfg, policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		require.Equal(t, http.StatusCreated, res.Code)
+
+		h = newTestHandlerWithStorage(t, storage, cfg, policies)
+		assert.Len(t, packages(t, h, "main"), 1)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		h := newTestHandler(t, testHostedConfig(t, "testpkg.cue"), policies)
+		for _, token := range []string{"", "wrong-token"} {
+			res := upload(t, h, http.MethodPut, "/debian/upload/test", token, deb)
+			assert.Equal(t, http.StatusUnauthorized, res.Code)
+		}
+		res := upload(t, h, http.MethodGet, "/debian/upload/test", testUploadToken, nil)
+		assert.Equal(t, http.StatusMethodNotAllowed, res.Code)
+		assert.Empty(t, packages(t, h, "main"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		h := newTestHandler(t, testHostedConfig(t, "testpkg.cue"), policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, []byte("not a deb"))
+		assert.Equal(t, http.StatusBadRequest, res.Code)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		cfg := testHostedConfig(t, "testpkg.cue")
+		cfg.Source.Hosted.MaxUploadSize = int64(len(deb)) - 1
+		h := newTestHandler(t, cfg, policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, res.Code)
+		assert.Empty(t, packages(t, h, "main"))
+
+		cfg.Source.Hosted.MaxUploadSize = int64(len(deb))
+		h = newTestHandler(t, cfg, policies)
+		res = upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		assert.Equal(t, http.StatusCreated, res.Code)
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		h := newTestHandler(t, testHostedConfig(t, "nothing.cue"), policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		assert.Empty(t, packages(t, h, "main"))
+	})
+
+	t.Run("not hosted", func(t *testing.T) {
+		mirror := newTestMirror(t)
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		res := upload(t, h, http.MethodPut, "/debian/upload/test", testUploadToken, deb)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -0,0 +1,215 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// signingKey is a private key that signs a repository during its validity window.
+type signingKey struct {
+	entity    *openpgp.Entity
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// signs reports whether the key should sign at a point in time.
+func (k signingKey) signs(t time.Time) bool {
+	if !k.notBefore.IsZero() && t.Before(k.notBefore) {
+		return false
+	}
+	return k.notAfter.IsZero() || t.Before(k.notAfter)
+}
+
+// published reports whether the public key should be served at a point in time.
+// Keys are published before they sign, so clients can trust them before a rotation.
+func (k signingKey) published(t time.Time) bool {
+	return k.notAfter.IsZero() || t.Before(k.notAfter)
+}
+
+// readKeys loads the repository's signing keys, from KeyPath and Keys.
+func readKeys(cfg *RepositoryConfig) ([]signingKey, error) {
+	keyCfgs := cfg.Keys
+	if cfg.KeyPath != "" {
+		keyCfgs = append([]SigningKeyConfig{{Path: cfg.KeyPath}}, keyCfgs...)
+	}
+	if len(keyCfgs) == 0 {
+		return nil, fmt.Errorf("missing key")
+	}
+
+	var keys []signingKey
+	for _, keyCfg := range keyCfgs {
+		entities, err := readKey(keyCfg.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", keyCfg.Path, err)
+		}
+		for _, e := range entities {
+			if e.PrivateKey == nil {
+				return nil, fmt.Errorf("%s: key %s is not a private key", keyCfg.Path, e.PrimaryKey.KeyIdString())
+			}
+			keys = append(keys, signingKey{entity: e, notBefore: keyCfg.NotBefore, notAfter: keyCfg.NotAfter})
+		}
+	}
+	return keys, nil
+}
+
+func readKey(path string) (openpgp.EntityList, error) {
+	keyIn, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer keyIn.Close()
+	key, err := openpgp.ReadArmoredKeyRing(keyIn)
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+	return key, nil
+}
+
+// signers returns the keys that sign at a point in time.
+func (rh *repositoryHandler) signers(t time.Time) ([]*openpgp.Entity, error) {
+	var signers []*openpgp.Entity
+	for _, k := range rh.keys {
+		if k.signs(t) {
+			signers = append(signers, k.entity)
+		}
+	}
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("repository %s has no valid signing key", rh.name)
+	}
+	return signers, nil
+}
+
+// clearSign writes the InRelease file, signed by every valid key.
+func (rh *repositoryHandler) clearSign(release []byte, t time.Time) ([]byte, error) {
+	signers, err := rh.signers(t)
+	if err != nil {
+		return nil, err
+	}
+	privateKeys := make([]*packet.PrivateKey, 0, len(signers))
+	for _, e := range signers {
+		privateKeys = append(privateKeys, e.PrivateKey)
+	}
+
+	var buf bytes.Buffer
+	enc, err := clearsign.EncodeMulti(&buf, privateKeys, &packet.Config{Time: func() time.Time { return t }})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := enc.Write(release); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	if _, err = fmt.Fprintln(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// detachSign writes the Release.gpg file, with a signature from every valid key.
+func (rh *repositoryHandler) detachSign(release []byte, t time.Time) ([]byte, error) {
+	signers, err := rh.signers(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.SignatureType, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range signers {
+		if err := openpgp.DetachSign(w, e, bytes.NewReader(release), &packet.Config{Time: func() time.Time { return t }}); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if _, err = fmt.Fprintln(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// HandlePublicKey serves the armored public keyring of a repository, for clients to use as `signed-by`.
+// The keyring includes keys that will sign in the future, and excludes keys that have expired.
+func (h Handler) HandlePublicKey(_ context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, ok := h.repos[req.PathVars["repository"]]
+	if !ok {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	keyring, err := rh.armoredPublicKeys(h.now())
+	if err != nil {
+		return nil, err
+	}
+	return &hedge.HttpResponse{
+		ContentType: "application/pgp-keys",
+		Body:        keyring,
+	}, nil
+}
+
+// HandleKeyring serves the public keyring of a repository in binary form, for `/etc/apt/keyrings`.
+func (h Handler) HandleKeyring(_ context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, ok := h.repos[req.PathVars["repository"]]
+	if !ok {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	var buf bytes.Buffer
+	if err := rh.writePublicKeys(&buf, h.now()); err != nil {
+		return nil, err
+	}
+	return &hedge.HttpResponse{
+		ContentType: "application/pgp-keys",
+		Body:        buf.Bytes(),
+	}, nil
+}
+
+// armoredPublicKeys returns the armored public keyring at a point in time.
+func (rh *repositoryHandler) armoredPublicKeys(t time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := rh.writePublicKeys(w, t); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if _, err = fmt.Fprintln(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writePublicKeys writes the published public keys at a point in time.
+func (rh *repositoryHandler) writePublicKeys(w io.Writer, t time.Time) error {
+	for _, k := range rh.keys {
+		if !k.published(t) {
+			continue
+		}
+		if err := k.entity.Serialize(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
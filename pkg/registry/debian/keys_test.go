@@ -0,0 +1,138 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+// newTestKey writes a new private key, returning its path and public keyring.
+func newTestKey(t *testing.T) (string, openpgp.EntityList) {
+	t.Helper()
+	e, err := openpgp.NewEntity("hedge test", "", "test@example.com", nil)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.asc")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	w, err := armor.Encode(f, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.SerializePrivate(w, nil))
+	require.NoError(t, w.Close())
+	return path, openpgp.EntityList{e}
+}
+
+func TestHandler_KeyRotation(t *testing.T) {
+	mirror := newTestMirror(t)
+	oldKey := readTestKey(t)
+	newKeyPath, newKey := newTestKey(t)
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+
+	now := time.Now()
+	cases := map[string]struct {
+		keys      []debian.SigningKeyConfig
+		signed    []openpgp.EntityList
+		unsigned  []openpgp.EntityList
+		published []openpgp.EntityList
+	}{
+		"before rotation": {
+			keys: []debian.SigningKeyConfig{
+				{Path: testPrivateKey},
+				{Path: newKeyPath, NotBefore: now.Add(time.Hour)},
+			},
+			signed:    []openpgp.EntityList{oldKey},
+			unsigned:  []openpgp.EntityList{newKey},
+			published: []openpgp.EntityList{oldKey, newKey},
+		},
+		"during rotation": {
+			keys: []debian.SigningKeyConfig{
+				{Path: testPrivateKey, NotAfter: now.Add(time.Hour)},
+				{Path: newKeyPath, NotBefore: now.Add(-time.Hour)},
+			},
+			signed:    []openpgp.EntityList{oldKey, newKey},
+			published: []openpgp.EntityList{oldKey, newKey},
+		},
+		"after rotation": {
+			keys: []debian.SigningKeyConfig{
+				{Path: testPrivateKey, NotAfter: now.Add(-time.Hour)},
+				{Path: newKeyPath, NotBefore: now.Add(-2 * time.Hour)},
+			},
+			signed:    []openpgp.EntityList{newKey},
+			unsigned:  []openpgp.EntityList{oldKey},
+			published: []openpgp.EntityList{newKey},
+		},
+	}
+
+	for label, tc := range cases {
+		t.Run(label, func(t *testing.T) {
+			repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+			repoCfg.KeyPath = ""
+			repoCfg.Keys = tc.keys
+			h := newTestHandler(t, repoCfg, policies)
+
+			inRelease := get(t, h, "/debian/dists/test/InRelease")
+			require.Equal(t, http.StatusOK, inRelease.Code)
+			release := get(t, h, "/debian/dists/test/Release")
+			require.Equal(t, http.StatusOK, release.Code)
+			signature := get(t, h, "/debian/dists/test/Release.gpg")
+			require.Equal(t, http.StatusOK, signature.Code)
+
+			verify := func(keyring openpgp.EntityList) error {
+				_, err := debian.NewParser(observability.NoopTracer).Release(context.Background(), bytes.NewReader(inRelease.Body.Bytes()), keyring)
+				if err != nil {
+					return err
+				}
+				_, err = openpgp.CheckArmoredDetachedSignature(keyring, bytes.NewReader(release.Body.Bytes()), bytes.NewReader(signature.Body.Bytes()), nil)
+				return err
+			}
+			for _, keyring := range tc.signed {
+				assert.NoError(t, verify(keyring))
+			}
+			for _, keyring := range tc.unsigned {
+				assert.Error(t, verify(keyring))
+			}
+
+			res := get(t, h, "/debian/keys/test.asc")
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, "application/pgp-keys", res.Header().Get("Content-Type"))
+			published, err := openpgp.ReadArmoredKeyRing(res.Body)
+			require.NoError(t, err)
+			var publishedIDs, expectedIDs []uint64
+			for _, e := range published {
+				assert.Nil(t, e.PrivateKey)
+				publishedIDs = append(publishedIDs, e.PrimaryKey.KeyId)
+			}
+			for _, keyring := range tc.published {
+				expectedIDs = append(expectedIDs, keyring[0].PrimaryKey.KeyId)
+			}
+			assert.ElementsMatch(t, expectedIDs, publishedIDs)
+		})
+	}
+
+	t.Run("no valid key", func(t *testing.T) {
+		repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.KeyPath = ""
+		repoCfg.Keys = []debian.SigningKeyConfig{{Path: testPrivateKey, NotAfter: now.Add(-time.Hour)}}
+		h := newTestHandler(t, repoCfg, policies)
+		res := get(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+	})
+
+	t.Run("unknown repository", func(t *testing.T) {
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		res := get(t, h, "/debian/keys/other.asc")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -2,12 +2,18 @@ package debian
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type LoadReleaseArgs struct {
-	MirrorURL     string
+	// MirrorURLs serve the release, in order of preference.
+	MirrorURLs    []string
 	SigningKey    string
 	Dist          string
 	Architectures []string
@@ -20,6 +26,7 @@ type ReleaseLoader interface {
 
 type LoadPackagesArgs struct {
 	Release      *hedge.DebianRelease
+	Component    Component
 	Architecture Architecture
 }
 
@@ -27,47 +34,64 @@ type PackagesLoader interface {
 	LoadPackages(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error)
 }
 
-// type FilteredPackageLoader struct {
-// 	tracer  trace.Tracer
-// 	wrapped PackagesLoader
-// 	pred    filter.Predicate[Package]
-// 	rekor   signature.RekorFinder
-// }
-
-// func NewFilteredPackageLoader(tracer trace.Tracer, wrapped PackagesLoader, rekor signature.RekorFinder, pred filter.Predicate[Package]) *FilteredPackageLoader {
-// 	return &FilteredPackageLoader{
-// 		tracer:  tracer,
-// 		wrapped: wrapped,
-// 		rekor:   rekor,
-// 		pred:    pred,
-// 	}
-// }
-
-// // func (p FilteredPackageLoader) BaseURL() string { return p.wrapped.BaseURL() }
-// func (p FilteredPackageLoader) LoadPackages(ctx context.Context, r *Release, arch Architecture) ([]Package, error) {
-// 	ctx, span := p.tracer.Start(ctx, "debianfilter.LoadPackages", trace.WithAttributes(attrArchitecture(arch)))
-// 	defer span.End()
-
-// 	pkgs, err := p.wrapped.LoadPackages(ctx, r, arch)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	decorated := make([]Package, 0, len(pkgs))
-// 	for _, pkg := range pkgs {
-// 		digest, err := hex.DecodeString(pkg.Sha256)
-// 		if err != nil {
-// 			return nil, err
-// 		}
-// 		if signer, err := p.rekor.GetSignature(ctx, digest); err != nil {
-// 			return nil, err
-// 		} else if signer != nil {
-// 			s, _ := json.Marshal(signer)
-// 			pkg.RekorRaw = string(s)
-// 		}
-
-// 		decorated = append(decorated, pkg)
-// 	}
-
-// 	return filter.FilterSlice(ctx, p.pred, decorated...)
-// }
+type LoadSourcesArgs struct {
+	Release   *hedge.DebianRelease
+	Component Component
+}
+
+type SourcesLoader interface {
+	LoadSources(ctx context.Context, args LoadSourcesArgs) (*hedge.DebianSources, error)
+}
+
+type LoadTranslationsArgs struct {
+	Release   *hedge.DebianRelease
+	Component Component
+	Language  string
+}
+
+// LoadPoolFileArgs identifies a file in a release's pool, with the expectations from a verified index.
+type LoadPoolFileArgs struct {
+	Release  *hedge.DebianRelease
+	Filename string
+	Size     uint64
+	Sha256   []byte
+}
+
+// PoolLoader fetches files from a pool. Implementations must verify the file's Size and Sha256.
+type PoolLoader interface {
+	LoadPoolFile(ctx context.Context, args LoadPoolFileArgs) ([]byte, error)
+}
+
+// FilteredPackagesLoader removes packages that are not allowed by a policy.
+type FilteredPackagesLoader struct {
+	tracer  trace.Tracer
+	wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	pred    filter.Predicate[*hedge.DebianPackage]
+}
+
+var _ PackagesLoader = (*FilteredPackagesLoader)(nil)
+
+func NewFilteredPackagesLoader(tracer trace.Tracer, wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages], pred filter.Predicate[*hedge.DebianPackage]) *FilteredPackagesLoader {
+	return &FilteredPackagesLoader{
+		tracer:  tracer,
+		wrapped: wrapped,
+		pred:    pred,
+	}
+}
+
+func (p FilteredPackagesLoader) LoadPackages(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+	ctx, span := p.tracer.Start(ctx, "debian.FilteredPackagesLoader.LoadPackages", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
+	defer span.End()
+
+	pkgs, err := p.wrapped(ctx, args)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+
+	filtered, err := filter.FilterSlice(ctx, p.pred, pkgs.Packages...)
+	if err != nil {
+		return nil, observability.CaptureError(span, fmt.Errorf("filtering packages: %w", err))
+	}
+	span.SetAttributes(attrPackageCount(len(filtered)))
+	return &hedge.DebianPackages{Packages: filtered}, nil
+}
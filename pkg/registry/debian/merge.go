@@ -0,0 +1,274 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// source is where some of a repository's packages come from.
+type source struct {
+	name     string
+	priority int
+
+	// release loads the source's release metadata, using releaseArgs.
+	release     cached.Function[LoadReleaseArgs, *hedge.DebianRelease]
+	releaseArgs LoadReleaseArgs
+	// upstream are all packages from the source, before policies are applied.
+	upstream cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	// filtered are the upstream packages allowed by the source's policies.
+	filtered cached.Function[LoadPackagesArgs, *hedge.DebianPackages]
+	pool     PoolLoader
+	policy   componentPolicy
+	// hosted accepts uploads, if the source is hosted.
+	hosted *HostedRepository
+	// sources are the source packages, if the source has them.
+	sources cached.Function[LoadSourcesArgs, *hedge.DebianSources]
+	// contents and translations are the source's Contents and Translation indices, if the source has them.
+	contents     cached.Function[LoadPackagesArgs, *hedge.DebianContents]
+	translations cached.Function[LoadTranslationsArgs, *hedge.DebianTranslations]
+}
+
+// mergedSource combines several sources into one release.
+// Filenames are moved under the source's name, like `pool/{source}/main/v/vim/vim_8.2_amd64.deb`, so pool files can
+// be fetched from the right source.
+type mergedSource struct {
+	tracer    trace.Tracer
+	dist      string
+	sources   []*source
+	byName    map[string]*source
+	conflicts ConflictResolution
+}
+
+var (
+	_ ReleaseLoader = (*mergedSource)(nil)
+	_ PoolLoader    = (*mergedSource)(nil)
+)
+
+func newMergedSource(tracer trace.Tracer, dist string, sources []*source, conflicts ConflictResolution) (*mergedSource, error) {
+	switch conflicts {
+	case "":
+		conflicts = ConflictsByPriority
+	case ConflictsByPriority, ConflictsByVersion:
+	default:
+		return nil, fmt.Errorf("unknown conflict resolution %q", conflicts)
+	}
+
+	byName := make(map[string]*source, len(sources))
+	for _, src := range sources {
+		if src.name == "" || strings.Contains(src.name, "/") {
+			return nil, fmt.Errorf("invalid source name %q", src.name)
+		}
+		if _, ok := byName[src.name]; ok {
+			return nil, fmt.Errorf("duplicate source name %q", src.name)
+		}
+		byName[src.name] = src
+	}
+	return &mergedSource{
+		tracer:    tracer,
+		dist:      dist,
+		sources:   sources,
+		byName:    byName,
+		conflicts: conflicts,
+	}, nil
+}
+
+// LoadRelease merges the releases of every source. The first source provides the release's metadata.
+func (m *mergedSource) LoadRelease(ctx context.Context, _ LoadReleaseArgs) (*hedge.DebianRelease, error) {
+	ctx, span := m.tracer.Start(ctx, "debian.mergedSource.LoadRelease", trace.WithAttributes(attrDist(m.dist)))
+	defer span.End()
+
+	var merged *hedge.DebianRelease
+	for _, src := range m.sources {
+		release, err := src.release(ctx, src.releaseArgs)
+		if err != nil {
+			return nil, observability.CaptureError(span, fmt.Errorf("loading release of source %s: %w", src.name, err))
+		}
+		// The merged release changes when any source changes, which renders the repository again:
+		digests := make(map[string]*hedge.DebianRelease_DigestedFile, len(release.Digests))
+		for fn, digest := range release.Digests {
+			digests[fmt.Sprintf("%s/%s", src.name, fn)] = digest
+		}
+		if merged == nil {
+			merged = proto.Clone(release).(*hedge.DebianRelease)
+			merged.Dist = m.dist
+			merged.Digests = digests
+			merged.MirrorUrl = ""
+			merged.MirrorUrls = nil
+			continue
+		}
+		for fn, digest := range digests {
+			merged.Digests[fn] = digest
+		}
+		merged.Components = appendMissing(merged.Components, release.Components...)
+		merged.Architectures = appendMissing(merged.Architectures, release.Architectures...)
+		if release.Date.AsTime().After(merged.Date.AsTime()) {
+			merged.Date = release.Date
+		}
+	}
+	return merged, nil
+}
+
+// LoadUpstream loads the packages of every source, before policies are applied.
+func (m *mergedSource) LoadUpstream(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+	pkgs, err := m.loadPackages(ctx, args, func(src *source) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] { return src.upstream })
+	if err != nil {
+		return nil, err
+	}
+	return &hedge.DebianPackages{Packages: pkgs}, nil
+}
+
+// LoadFiltered loads the packages of every source allowed by the source's policies.
+func (m *mergedSource) LoadFiltered(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+	pkgs, err := m.loadPackages(ctx, args, func(src *source) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] { return src.filtered })
+	if err != nil {
+		return nil, err
+	}
+	return &hedge.DebianPackages{Packages: pkgs}, nil
+}
+
+func (m *mergedSource) loadPackages(ctx context.Context, args LoadPackagesArgs, loader func(*source) cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) ([]*hedge.DebianPackage, error) {
+	var merged []*hedge.DebianPackage
+	for _, src := range m.sources {
+		release, err := src.release(ctx, src.releaseArgs)
+		if err != nil {
+			return nil, fmt.Errorf("loading release of source %s: %w", src.name, err)
+		}
+		// Sources don't need to provide every component and architecture:
+		if !contains(release.Components, string(args.Component)) || !contains(release.Architectures, string(args.Architecture)) {
+			continue
+		}
+		pkgs, err := loader(src)(ctx, LoadPackagesArgs{
+			Release:      release,
+			Component:    args.Component,
+			Architecture: args.Architecture,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("loading packages of source %s: %w", src.name, err)
+		}
+		for _, pkg := range pkgs.Packages {
+			pkg := proto.Clone(pkg).(*hedge.DebianPackage)
+			pkg.Filename = path.Join("pool", src.name, strings.TrimPrefix(pkg.Filename, "pool/"))
+			merged = append(merged, pkg)
+		}
+	}
+	return merged, nil
+}
+
+// resolvingConflicts removes packages that lost a conflict with a package of the same name and architecture
+// from another source.
+func (m *mergedSource) resolvingConflicts(wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		pkgs, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+
+		type packageKey struct{ name, arch string }
+		winners := make(map[packageKey]*hedge.DebianPackage, len(pkgs.Packages))
+		for _, pkg := range pkgs.Packages {
+			key := packageKey{name: pkg.Name, arch: pkg.Architecture}
+			if winner, ok := winners[key]; !ok || m.preferred(pkg, winner) {
+				winners[key] = pkg
+			}
+		}
+
+		resolved := make([]*hedge.DebianPackage, 0, len(winners))
+		for _, pkg := range pkgs.Packages {
+			winner := winners[packageKey{name: pkg.Name, arch: pkg.Architecture}]
+			switch m.conflicts {
+			case ConflictsByVersion:
+				if pkg != winner {
+					continue
+				}
+			default:
+				// The winning source may provide several versions:
+				if m.sourceOf(pkg) != m.sourceOf(winner) {
+					continue
+				}
+			}
+			resolved = append(resolved, pkg)
+		}
+		return &hedge.DebianPackages{Packages: resolved}, nil
+	}
+}
+
+// preferred reports whether package a wins a conflict with package b.
+func (m *mergedSource) preferred(a, b *hedge.DebianPackage) bool {
+	if m.conflicts == ConflictsByVersion {
+		if c, err := CompareVersions(a.Version, b.Version); err == nil && c != 0 {
+			return c > 0
+		}
+	}
+	srcA, srcB := m.sourceOf(a), m.sourceOf(b)
+	if srcA.priority != srcB.priority {
+		return srcA.priority > srcB.priority
+	}
+	// Otherwise the first configured source wins:
+	return m.index(srcA) < m.index(srcB)
+}
+
+func (m *mergedSource) index(src *source) int {
+	for i, s := range m.sources {
+		if s == src {
+			return i
+		}
+	}
+	return len(m.sources)
+}
+
+// sourceOf returns the source of a merged package.
+func (m *mergedSource) sourceOf(pkg *hedge.DebianPackage) *source {
+	src, _, _ := m.sourceFile(pkg.Filename)
+	return src
+}
+
+// sourceFile maps a merged filename to its source, and the source's filename.
+func (m *mergedSource) sourceFile(filename string) (*source, string, error) {
+	name, rest, _ := strings.Cut(strings.TrimPrefix(filename, "pool/"), "/")
+	src, ok := m.byName[name]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown source in filename %q", filename)
+	}
+	return src, path.Join("pool", rest), nil
+}
+
+func (m *mergedSource) LoadPoolFile(ctx context.Context, args LoadPoolFileArgs) ([]byte, error) {
+	src, filename, err := m.sourceFile(args.Filename)
+	if err != nil {
+		return nil, err
+	}
+	release, err := src.release(ctx, src.releaseArgs)
+	if err != nil {
+		return nil, fmt.Errorf("loading release of source %s: %w", src.name, err)
+	}
+	args.Release = release
+	args.Filename = filename
+	return src.pool.LoadPoolFile(ctx, args)
+}
+
+// policy returns the policy of the source of a merged filename.
+func (m *mergedSource) policy(component Component, filename string) filter.Predicate[*hedge.DebianPackage] {
+	src, _, err := m.sourceFile(filename)
+	if err != nil {
+		return func(context.Context, *hedge.DebianPackage) (bool, error) { return false, err }
+	}
+	return src.policy(component)
+}
+
+func appendMissing(values []string, add ...string) []string {
+	for _, v := range add {
+		if !contains(values, v) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
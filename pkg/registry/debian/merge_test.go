@@ -0,0 +1,154 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+func TestHandler_MergedSources(t *testing.T) {
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	gh := newTestGitHub(t)
+	stable := newTestMirror(t)
+	// updates has an older testpkg, using the same file:
+	updates := newTestMirror(t)
+	testPkg, err := debian.NewParser(observability.NoopTracer).PackageFromDeb(context.Background(), bytes.NewReader(deb))
+	require.NoError(t, err)
+	testPkg.Version = "1.0"
+	testPkg.Filename = testDebPath
+	testPkg.Size = uint64(len(deb))
+	digest := sha256.Sum256(deb)
+	testPkg.Sha256 = digest[:]
+	var main bytes.Buffer
+	require.NoError(t, debian.WriteControlFile(&main, debian.ParagraphFromPackage(testPkg)))
+	updates.SetPackages(t, "main", main.Bytes())
+
+	policies := map[string]string{
+		"testpkg.cue":     `name: "testpkg"`,
+		"alien-arena.cue": `name: =~"^alien-arena"`,
+	}
+	newMergedConfig := func(conflicts debian.ConflictResolution) *debian.RepositoryConfig {
+		repoCfg := testRepositoryConfig("", "", "testpkg.cue", "alien-arena.cue")
+		repoCfg.Source = debian.SourceConfig{}
+		repoCfg.Conflicts = conflicts
+		repoCfg.Sources = []debian.SourceConfig{
+			{
+				Name:     "stable",
+				Upstream: testRepositoryConfig(stable.URL, stable.PubKey).Source.Upstream,
+			},
+			{
+				Name:     "updates",
+				Priority: 10,
+				Upstream: testRepositoryConfig(updates.URL, updates.PubKey).Source.Upstream,
+				Policies: &filter.Config{AnyOf: []string{"testpkg.cue"}},
+			},
+			{
+				Name: "github",
+				GitHub: &debian.GitHubConfig{
+					URL:          gh.URL,
+					Release:      &hedge.DebianRelease{Architectures: []string{"amd64"}},
+					Repositories: []string{"test/testpkg"},
+				},
+			},
+		}
+		return repoCfg
+	}
+	newMergedHandler := func(t *testing.T, conflicts debian.ConflictResolution) http.Handler {
+		return newTestHandler(t, newMergedConfig(conflicts), policies)
+	}
+
+	packages := func(t *testing.T, h http.Handler, component string) []*hedge.DebianPackage {
+		t.Helper()
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, "test", release.Codename)
+		assert.Equal(t, []string{"main", "contrib"}, release.Components)
+		digest, ok := release.Digests[component+"/binary-amd64/Packages"]
+		require.True(t, ok)
+
+		res := get(t, h, "/debian/dists/test/"+component+"/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		actualDigest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		return pkgs
+	}
+
+	cases := map[debian.ConflictResolution]struct {
+		filename string
+		version  string
+	}{
+		debian.ConflictsByPriority: {
+			filename: "dists/test/pool/updates/main/t/testpkg/testpkg_1.2.3_amd64.deb",
+			version:  "1.0",
+		},
+		debian.ConflictsByVersion: {
+			// stable and github have the same version and priority, so the first configured source wins:
+			filename: "dists/test/pool/stable/main/t/testpkg/testpkg_1.2.3_amd64.deb",
+			version:  "1.2.3",
+		},
+	}
+	for conflicts, tc := range cases {
+		t.Run(string(conflicts), func(t *testing.T) {
+			h := newMergedHandler(t, conflicts)
+
+			pkgs := packages(t, h, "main")
+			require.Len(t, pkgs, 1)
+			assert.Equal(t, tc.version, pkgs[0].Version)
+			assert.Equal(t, tc.filename, pkgs[0].Filename)
+
+			res := get(t, h, "/debian/"+tc.filename)
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, deb, res.Body.Bytes())
+
+			// Packages that lost a conflict are refused:
+			res = get(t, h, "/debian/dists/test/pool/github/test/testpkg/releases/download/v1.2.3/testpkg_1.2.3_amd64.deb")
+			assert.Equal(t, http.StatusForbidden, res.Code)
+		})
+	}
+
+	t.Run("source policies", func(t *testing.T) {
+		h := newMergedHandler(t, debian.ConflictsByPriority)
+		// Only stable's policy allows alien-arena:
+		var names []string
+		for _, pkg := range packages(t, h, "contrib") {
+			names = append(names, pkg.Name)
+			assert.Contains(t, pkg.Filename, "dists/test/pool/stable/")
+		}
+		assert.ElementsMatch(t, []string{"alien-arena", "alien-arena-server"}, names)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for label, mutate := range map[string]func(*debian.RepositoryConfig){
+			"duplicate name":     func(cfg *debian.RepositoryConfig) { cfg.Sources[1].Name = cfg.Sources[0].Name },
+			"missing name":       func(cfg *debian.RepositoryConfig) { cfg.Sources[0].Name = "" },
+			"conflicts":          func(cfg *debian.RepositoryConfig) { cfg.Conflicts = "newest" },
+			"source and sources": func(cfg *debian.RepositoryConfig) { cfg.Source = cfg.Sources[0] },
+		} {
+			t.Run(label, func(t *testing.T) {
+				repoCfg := newMergedConfig(debian.ConflictsByPriority)
+				mutate(repoCfg)
+				storage := cached.InMemory[string, []byte]()
+				_, err := debian.NewHandler(base.NewCachedMux(observability.NoopTracer, storage), observability.NoopTracer, storage, &http.Client{}, registry.EcosystemConfig{
+					Repositories: map[string]registry.RepositoryConfig{"test": repoCfg},
+					Policies:     policies,
+				})
+				assert.Error(t, err)
+			})
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// mirrorHedgeDelay is how long a mirror can take before the next mirror is also tried.
+const mirrorHedgeDelay = 2 * time.Second
+
+type mirrorResult[V any] struct {
+	mirror string
+	value  V
+	err    error
+}
+
+// fromMirrors returns the first successful result of fetch from mirrors, and the mirror that provided it.
+// Mirrors are tried in order: the next mirror is started when every running attempt has failed, or when the
+// running attempts have taken longer than hedgeDelay. fetch must verify what it fetches, so an untrustworthy mirror
+// is a failed mirror.
+func fromMirrors[V any](ctx context.Context, tracer trace.Tracer, hedgeDelay time.Duration, mirrors []string, fetch func(context.Context, string) (V, error)) (V, string, error) {
+	var zero V
+	if len(mirrors) == 0 {
+		return zero, "", fmt.Errorf("no mirrors configured")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan mirrorResult[V], len(mirrors))
+	started := 0
+	start := func() {
+		mirror := mirrors[started]
+		started++
+		go func() {
+			ctx, span := tracer.Start(ctx, "debian.mirror", trace.WithAttributes(attrMirror(mirror)))
+			defer span.End()
+			begin := time.Now()
+			v, err := fetch(ctx, mirror)
+			span.SetAttributes(attrMirrorLatency(time.Since(begin)), attrMirrorHealthy(err == nil))
+			if err != nil {
+				_ = observability.CaptureError(span, err)
+			}
+			results <- mirrorResult[V]{mirror: mirror, value: v, err: err}
+		}()
+	}
+
+	start()
+	hedgeTimer := time.NewTimer(hedgeDelay)
+	defer hedgeTimer.Stop()
+	var errs []string
+	for {
+		select {
+		case res := <-results:
+			if res.err == nil {
+				trace.SpanFromContext(ctx).SetAttributes(attrMirror(res.mirror))
+				return res.value, res.mirror, nil
+			}
+			errs = append(errs, fmt.Sprintf("%s: %v", res.mirror, res.err))
+			if len(errs) == len(mirrors) {
+				return zero, "", fmt.Errorf("all mirrors failed: %s", strings.Join(errs, "; "))
+			}
+			// Fail over immediately if nothing else is running:
+			if len(errs) == started {
+				start()
+				hedgeTimer.Reset(hedgeDelay)
+			}
+		case <-hedgeTimer.C:
+			if started < len(mirrors) {
+				start()
+				hedgeTimer.Reset(hedgeDelay)
+			}
+		case <-ctx.Done():
+			return zero, "", ctx.Err()
+		}
+	}
+}
+
+// releaseMirrors are the mirrors that serve a release, in order of preference.
+func releaseMirrors(release *hedge.DebianRelease) []string {
+	if len(release.MirrorUrls) > 0 {
+		return release.MirrorUrls
+	}
+	return []string{release.MirrorUrl}
+}
@@ -13,33 +13,47 @@ func PackageFromParagraph(graph Paragraph) (*hedge.DebianPackage, error) {
 	pkg := hedge.DebianPackage{
 		Name:          graph["Package"],
 		Architecture:  graph["Architecture"],
-		Breaks:        strings.Split(graph["Breaks"], ", "),
-		Conflicts:     strings.Split(graph["Conflicts"], ", "),
-		Depends:       strings.Split(graph["Depends"], ", "),
 		Description:   graph["Description"],
-		Enhances:      strings.Split(graph["Enhances"], ", "),
 		Essential:     graph["Essential"] == "yes",
 		Filename:      graph["Filename"],
 		Homepage:      graph["Homepage"],
 		Important:     graph["Important"] == "yes",
-		LuaVersions:   strings.Split(graph["LuaVersions"], " "),
+		LuaVersions:   strings.Split(graph["Lua-Versions"], " "),
 		Maintainer:    graph["Maintainer"],
 		Multiarch:     graph["Multi-Arch"],
-		PreDepends:    strings.Split(graph["Pre-Depends"], ", "),
 		Priority:      graph["Priority"],
 		Protected:     graph["Protected"] == "yes",
-		Provides:      strings.Split(graph["Provides"], ", "),
 		PythonVersion: graph["Python-Version"],
-		Recommends:    strings.Split(graph["Recommends"], ", "),
-		Replaces:      strings.Split(graph["Replaces"], ", "),
-		RubyVersions:  strings.Split(graph["RubyVersions"], " "),
+		RubyVersions:  strings.Split(graph["Ruby-Versions"], " "),
 		Section:       graph["Section"],
 		Source:        graph["Source"],
-		Suggests:      strings.Split(graph["Suggests"], ", "),
 		Tags:          strings.Split(graph["Tag"], ", "),
 		Version:       graph["Version"],
 	}
 
+	relationships := map[string]*[]*hedge.DebianDependency{
+		"Breaks":      &pkg.Breaks,
+		"Conflicts":   &pkg.Conflicts,
+		"Depends":     &pkg.Depends,
+		"Enhances":    &pkg.Enhances,
+		"Pre-Depends": &pkg.PreDepends,
+		"Recommends":  &pkg.Recommends,
+		"Replaces":    &pkg.Replaces,
+		"Suggests":    &pkg.Suggests,
+	}
+	for k, field := range relationships {
+		deps, err := ParseDependencies(graph[k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", k, err)
+		}
+		*field = deps
+	}
+	provides, err := ParseRelations(graph["Provides"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid Provides: %w", err)
+	}
+	pkg.Provides = provides
+
 	if v, ok := graph["Installed-Size"]; ok {
 		i, err := strconv.Atoi(v)
 		if err != nil {
@@ -55,7 +69,7 @@ func PackageFromParagraph(graph Paragraph) (*hedge.DebianPackage, error) {
 		pkg.Size = uint64(i)
 	}
 
-	if v, ok := graph["MD5Sum"]; ok {
+	if v, ok := graph["MD5sum"]; ok {
 		digest, err := hex.DecodeString(v)
 		if err != nil {
 			return nil, fmt.Errorf("invalid MD5sum: %s", v)
@@ -75,19 +89,11 @@ func PackageFromParagraph(graph Paragraph) (*hedge.DebianPackage, error) {
 		case "Package", "Architecture", "Breaks", "Conflicts", "Depends", "Description", "Enhances", "Essential", "Filename", "Homepage", "Important", "Installed-Size", "Lua-Versions", "Maintainer", "MD5sum", "Multi-Arch",
 			"Pre-Depends", "Priority", "Protected", "Provides", "Python-Version", "Recommends", "Replaces", "Ruby-Versions", "Section", "SHA256", "Size", "Source", "Suggests", "Tag", "Version":
 			// Mapped above
-		case "Build-Ids", "Built-Using", "Build-Essential",
-			"Cnf-Extra-Commands", "Cnf-Ignore-Commands", "Cnf-Priority-Bonus", "Cnf-Visible-Pkgname",
-			"Description-md5",
-			"Efi-Vendor",
-			"Gstreamer-Decoders", "Gstreamer-Elements", "Gstreamer-Encoders", "Gstreamer-Uri-Sinks", "Gstreamer-Uri-Sources", "Gstreamer-Version",
-			"Ghc-Package",
-			"Go-Import-Path",
-			"Postgresql-Catversion",
-			"Python-Egg-Name",
-			"X-Cargo-Built-Using":
-			// drop
 		default:
-			return nil, fmt.Errorf("unexpected key %q in paragraph: %v", k, v)
+			if pkg.ExtraFields == nil {
+				pkg.ExtraFields = map[string]string{}
+			}
+			pkg.ExtraFields[k] = v
 		}
 	}
 	return &pkg, nil
@@ -101,37 +107,44 @@ func boolToDebian(b bool) string {
 }
 
 func ParagraphFromPackage(pkg *hedge.DebianPackage) Paragraph {
-	return Paragraph{
+	graph := Paragraph{
 		"Package":        pkg.Name,
 		"Architecture":   pkg.Architecture,
-		"Breaks":         strings.Join(pkg.Breaks, ", "),
-		"Conflicts":      strings.Join(pkg.Conflicts, ", "),
-		"Depends":        strings.Join(pkg.Depends, ", "),
+		"Breaks":         FormatDependencies(pkg.Breaks),
+		"Conflicts":      FormatDependencies(pkg.Conflicts),
+		"Depends":        FormatDependencies(pkg.Depends),
 		"Description":    pkg.Description,
-		"Enhances":       strings.Join(pkg.Enhances, ", "),
+		"Enhances":       FormatDependencies(pkg.Enhances),
 		"Essential":      boolToDebian(pkg.Essential),
 		"Filename":       pkg.Filename,
 		"Homepage":       pkg.Homepage,
 		"Important":      boolToDebian(pkg.Important),
 		"Installed-Size": strconv.FormatUint(pkg.InstalledSize, 10),
-		"LuaVersions":    strings.Join(pkg.LuaVersions, " "),
+		"Lua-Versions":   strings.Join(pkg.LuaVersions, " "),
 		"Maintainer":     pkg.Maintainer,
-		"MD5Sum":         hex.EncodeToString(pkg.Md5Sum),
+		"MD5sum":         hex.EncodeToString(pkg.Md5Sum),
 		"Multi-Arch":     pkg.Multiarch,
-		"Pre-Depends":    strings.Join(pkg.PreDepends, ", "),
+		"Pre-Depends":    FormatDependencies(pkg.PreDepends),
 		"Priority":       pkg.Priority,
 		"Protected":      boolToDebian(pkg.Protected),
-		"Provides":       strings.Join(pkg.Provides, ", "),
+		"Provides":       FormatRelations(pkg.Provides),
 		"Python-Version": pkg.PythonVersion,
-		"Recommends":     strings.Join(pkg.Recommends, ", "),
-		"Replaces":       strings.Join(pkg.Replaces, ", "),
+		"Recommends":     FormatDependencies(pkg.Recommends),
+		"Replaces":       FormatDependencies(pkg.Replaces),
 		"Ruby-Versions":  strings.Join(pkg.RubyVersions, " "),
 		"Section":        pkg.Section,
 		"Source":         pkg.Source,
 		"SHA256":         hex.EncodeToString(pkg.Sha256),
 		"Size":           strconv.FormatUint(pkg.Size, 10),
-		"Suggests":       strings.Join(pkg.Suggests, ", "),
+		"Suggests":       FormatDependencies(pkg.Suggests),
 		"Tag":            strings.Join(pkg.Tags, ", "),
 		"Version":        pkg.Version,
 	}
+	// Known fields take precedence over extra fields:
+	for k, v := range pkg.ExtraFields {
+		if _, ok := graph[k]; !ok {
+			graph[k] = v
+		}
+	}
+	return graph
 }
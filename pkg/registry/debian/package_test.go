@@ -1,16 +1,21 @@
 package debian_test
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
 	"encoding/hex"
+	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/filter"
 	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
 )
 
 func TestParsePackages(t *testing.T) {
@@ -32,20 +37,11 @@ func TestParsePackages(t *testing.T) {
 	assert.Equal(t, uint64(2017), pkg.InstalledSize)
 	assert.Equal(t, "Debian Games Team <pkg-games-devel@lists.alioth.debian.org>", pkg.Maintainer)
 	assert.Equal(t, "amd64", pkg.Architecture)
-	assert.Equal(t, []string{
-		"libc6 (>= 2.17)",
-		"libcurl3-gnutls (>= 7.16.2)",
-		"libfreetype6 (>= 2.3.5)",
-		"libgcc-s1 (>= 3.0)",
-		"libjpeg62-turbo (>= 1.3.1)",
-		"libstdc++6 (>= 5)",
-		"libvorbisfile3 (>= 1.1.2)",
-		"libx11-6",
-		"libxxf86vm1",
-		"zlib1g (>= 1:1.1.4)",
-		"libopenal1",
-		"alien-arena-data",
-	}, pkg.Depends)
+	require.Len(t, pkg.Depends, 12)
+	assert.Equal(t, &hedge.DebianRelation{Name: "libc6", VersionOperator: ">=", Version: "2.17"}, pkg.Depends[0].Alternatives[0])
+	assert.Equal(t, &hedge.DebianRelation{Name: "zlib1g", VersionOperator: ">=", Version: "1:1.1.4"}, pkg.Depends[9].Alternatives[0])
+	assert.Equal(t, &hedge.DebianRelation{Name: "alien-arena-data"}, pkg.Depends[11].Alternatives[0])
+	assert.Equal(t, "libc6 (>= 2.17), libcurl3-gnutls (>= 7.16.2), libfreetype6 (>= 2.3.5), libgcc-s1 (>= 3.0), libjpeg62-turbo (>= 1.3.1), libstdc++6 (>= 5), libvorbisfile3 (>= 1.1.2), libx11-6, libxxf86vm1, zlib1g (>= 1:1.1.4), libopenal1, alien-arena-data", debian.FormatDependencies(pkg.Depends))
 	assert.Equal(t, "Standalone 3D first person online deathmatch shooter", pkg.Description)
 	assert.Equal(t, "http://red.planetarena.org", pkg.Homepage)
 	assert.Equal(t, []string{
@@ -70,3 +66,86 @@ func TestParsePackages(t *testing.T) {
 	digest, _ := hex.DecodeString("3fcd4894851b100a4da3f05b94e13fd64e639b309fba4dda979052a422c31e8e")
 	assert.Equal(t, digest, pkg.Sha256)
 }
+
+func TestParser_EachPackage(t *testing.T) {
+	f, err := os.Open("testdata/bullseye_Packages.gz")
+	require.NoError(t, err)
+	defer f.Close()
+	gzR, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	// Parsing stops at the first error from the callback:
+	stop := errors.New("stop")
+	var names []string
+	err = debian.NewParser(observability.NoopTracer).EachPackage(context.Background(), gzR, func(pkg *hedge.DebianPackage) error {
+		names = append(names, pkg.Name)
+		if len(names) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"alien-arena", "alien-arena-server"}, names)
+}
+
+func TestPackageFromParagraph_ExtraFields(t *testing.T) {
+	graph := debian.Paragraph{
+		"Package":            "gstreamer1.0-plugins-good",
+		"Version":            "1.18.4-2",
+		"Description-md5":    "de2b3d0db5845c79b22ffc0c38842f1b",
+		"Gstreamer-Version":  "1.18",
+		"X-Ubuntu-Something": "yes",
+	}
+	pkg, err := debian.PackageFromParagraph(graph)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"Description-md5":    "de2b3d0db5845c79b22ffc0c38842f1b",
+		"Gstreamer-Version":  "1.18",
+		"X-Ubuntu-Something": "yes",
+	}, pkg.ExtraFields)
+
+	rendered := debian.ParagraphFromPackage(pkg)
+	for k, v := range graph {
+		assert.Equal(t, v, rendered[k], k)
+	}
+
+	// Known fields can not be overwritten:
+	pkg.ExtraFields["Package"] = "evil"
+	assert.Equal(t, "gstreamer1.0-plugins-good", debian.ParagraphFromPackage(pkg)["Package"])
+
+	t.Run("multiline", func(t *testing.T) {
+		in := strings.Join([]string{
+			"Package: test",
+			"Description: synopsis",
+			" long description",
+			" .",
+			"   verbatim",
+			"X-Unknown: first",
+			" second",
+			" .",
+			" third",
+			"",
+		}, "\n")
+		graphs, err := debian.ParseControlFile(strings.NewReader(in))
+		require.NoError(t, err)
+		require.Len(t, graphs, 1)
+		pkg, err := debian.PackageFromParagraph(graphs[0])
+		require.NoError(t, err)
+		assert.Equal(t, "first\nsecond\n.\nthird", pkg.ExtraFields["X-Unknown"])
+
+		var buf bytes.Buffer
+		require.NoError(t, debian.WriteControlFile(&buf, debian.ParagraphFromPackage(pkg)))
+		assert.Contains(t, buf.String(), "Description: synopsis\n long description\n .\n   verbatim\n")
+		assert.Contains(t, buf.String(), "X-Unknown: first\n second\n .\n third\n")
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		pred, err := filter.CuePoliciesToPredicate[*hedge.DebianPackage](map[string]string{
+			"gstreamer.cue": `extra_fields: "Gstreamer-Version": "1.18"`,
+		}, filter.Config{AnyOf: []string{"gstreamer.cue"}})
+		require.NoError(t, err)
+		ok, err := pred(context.Background(), pkg)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
@@ -3,11 +3,14 @@ package debian
 import (
 	"archive/tar"
 	"bytes"
-	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
@@ -16,7 +19,6 @@ import (
 	"github.com/blakesmith/ar"
 	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/proto/hedge/v1"
-	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -31,47 +33,55 @@ func NewParser(tracer trace.Tracer) Parser {
 }
 
 func (p Parser) Packages(ctx context.Context, in io.Reader) ([]*hedge.DebianPackage, error) {
-	ctx, span := p.tracer.Start(ctx, "debian.Parser.Packages")
-	defer span.End()
-
-	graphs, err := p.parseControlFile(ctx, in)
-	if err != nil {
-		return nil, observability.CaptureError(span, err)
-	}
-
-	pkgs, err := p.packagesFromParagraphs(ctx, graphs)
-	if err != nil {
-		return nil, observability.CaptureError(span, err)
+	var pkgs []*hedge.DebianPackage
+	if err := p.EachPackage(ctx, in, func(pkg *hedge.DebianPackage) error {
+		pkgs = append(pkgs, pkg)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	span.SetAttributes(attrPackageCount(len(pkgs)))
 	return pkgs, nil
 }
 
-func (p Parser) parseControlFile(ctx context.Context, in io.Reader) ([]Paragraph, error) {
-	_, span := p.tracer.Start(ctx, "debian.parser.parseControlFile")
+// EachPackage parses a Packages file one package at a time, so the file is never held in memory.
+// Parsing stops at the first error returned by fn.
+func (p Parser) EachPackage(ctx context.Context, in io.Reader, fn func(*hedge.DebianPackage) error) error {
+	_, span := p.tracer.Start(ctx, "debian.Parser.EachPackage")
 	defer span.End()
 
-	graphs, err := ParseControlFile(in)
+	var count int
+	err := eachParagraph(in, func(graph Paragraph) error {
+		pkg, err := PackageFromParagraph(graph)
+		if err != nil {
+			return fmt.Errorf("parsing package: %w", err)
+		}
+		count++
+		return fn(pkg)
+	})
+	span.SetAttributes(attrPackageCount(count))
 	if err != nil {
-		return nil, observability.CaptureError(span, err)
+		return observability.CaptureError(span, err)
 	}
-	span.SetAttributes(attribute.Int("debian.paragraph.count", len(graphs)))
-	return graphs, nil
+	return nil
 }
 
-func (p Parser) packagesFromParagraphs(ctx context.Context, graphs []Paragraph) ([]*hedge.DebianPackage, error) {
-	_, span := p.tracer.Start(ctx, "debian.Parser.packagesFromParagraphs")
-	defer span.End()
-
-	pkgs := make([]*hedge.DebianPackage, 0, len(graphs))
-	for _, graph := range graphs {
-		pkg, err := PackageFromParagraph(graph)
+// eachParagraph reads a control file one paragraph at a time.
+func eachParagraph(in io.Reader, fn func(Paragraph) error) error {
+	for r := NewControlReader(in); ; {
+		fields, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		graph, err := fields.Paragraph()
 		if err != nil {
-			return nil, observability.CaptureError(span, fmt.Errorf("parsing package: %w", err))
+			return err
+		}
+		if err := fn(graph); err != nil {
+			return err
 		}
-		pkgs = append(pkgs, pkg)
 	}
-	return pkgs, nil
 }
 
 func (p Parser) Release(ctx context.Context, in io.Reader, key openpgp.EntityList) (*hedge.DebianRelease, error) {
@@ -100,47 +110,147 @@ func (p Parser) Release(ctx context.Context, in io.Reader, key openpgp.EntityLis
 	if err != nil {
 		return nil, observability.CaptureError(span, fmt.Errorf("parsing release from paragraph: %w", err))
 	}
+	// An expired release may be replayed by a mirror to hide updates:
+	if release.ValidUntil != nil && p.now().After(release.ValidUntil.AsTime()) {
+		return nil, observability.CaptureError(span, fmt.Errorf("release expired at %s", release.ValidUntil.AsTime().Format(time.RFC1123)))
+	}
 	return release, nil
 }
 
+// PackageFromDeb parses a .deb archive. The package is read from the control archive, with a manifest of the files
+// in the data archive. Returns nil if the archive has no control file.
 func (p Parser) PackageFromDeb(ctx context.Context, in io.Reader) (*hedge.DebianPackage, error) {
+	ctx, span := p.tracer.Start(ctx, "debian.Parser.PackageFromDeb")
+	defer span.End()
+
+	var pkg *hedge.DebianPackage
+	var files []*hedge.DebianFile
 	for reader := ar.NewReader(in); ; {
 		hdr, err := reader.Next()
 		if errors.Is(err, io.EOF) {
 			break
 		} else if err != nil {
-			return nil, fmt.Errorf("reading archive: %w", err)
-		}
-		if hdr.Name != "control.tar.gz" {
-			continue
+			return nil, observability.CaptureError(span, fmt.Errorf("reading archive: %w", err))
 		}
 
-		gzR, err := gzip.NewReader(reader)
-		if err != nil {
-			return nil, fmt.Errorf("creating gzip reader: %w", err)
-		}
-		defer gzR.Close()
-
-		for tarR := tar.NewReader(gzR); ; {
-			hdr, err := tarR.Next()
-			if errors.Is(err, io.EOF) {
-				break
-			} else if err != nil {
-				return nil, fmt.Errorf("reading archive: %w", err)
+		// GNU ar terminates names with a slash:
+		name := strings.TrimSuffix(hdr.Name, "/")
+		switch {
+		case strings.HasPrefix(name, "control.tar"):
+			pkg, err = p.controlFromTar(ctx, reader, CompressionFromExtension(path.Ext(name)))
+			if err != nil {
+				return nil, observability.CaptureError(span, fmt.Errorf("reading %s: %w", name, err))
 			}
-
-			if hdr.Name != "./control" {
-				continue
+		case strings.HasPrefix(name, "data.tar"):
+			files, err = filesFromTar(reader, CompressionFromExtension(path.Ext(name)))
+			if err != nil {
+				return nil, observability.CaptureError(span, fmt.Errorf("reading %s: %w", name, err))
 			}
+		}
+	}
+	if pkg == nil {
+		return nil, nil
+	}
+	pkg.Files = files
+	span.SetAttributes(attrFileCount(len(files)))
+	return pkg, nil
+}
+
+// maintainerScripts are the control archive members that dpkg runs, or acts on, during installation.
+var maintainerScripts = map[string]struct{}{
+	"preinst":  {},
+	"postinst": {},
+	"prerm":    {},
+	"postrm":   {},
+	"config":   {},
+	"triggers": {},
+}
+
+func (p Parser) controlFromTar(ctx context.Context, in io.Reader, compression Compression) (*hedge.DebianPackage, error) {
+	r, err := compression.Reader(in)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var pkg *hedge.DebianPackage
+	scripts := map[string]*hedge.DebianScript{}
+	for tarR := tar.NewReader(r); ; {
+		hdr, err := tarR.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
 
+		name := strings.TrimPrefix(tarPath(hdr.Name), "/")
+		if name == "control" {
 			pkgs, err := p.Packages(ctx, tarR)
 			if err != nil {
 				return nil, fmt.Errorf("parsing control file: %w", err)
 			}
 			if len(pkgs) == 1 {
-				return pkgs[0], nil
+				pkg = pkgs[0]
 			}
+			continue
+		}
+		if _, ok := maintainerScripts[name]; !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		size, err := io.Copy(h, tarR)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		scripts[name] = &hedge.DebianScript{
+			Sha256: hex.EncodeToString(h.Sum(nil)),
+			Size:   uint64(size),
 		}
 	}
-	return nil, nil
+	if pkg != nil && len(scripts) > 0 {
+		pkg.MaintainerScripts = scripts
+	}
+	return pkg, nil
+}
+
+func filesFromTar(in io.Reader, compression Compression) ([]*hedge.DebianFile, error) {
+	r, err := compression.Reader(in)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var files []*hedge.DebianFile
+	for tarR := tar.NewReader(r); ; {
+		hdr, err := tarR.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+
+		f := &hedge.DebianFile{
+			Path:   tarPath(hdr.Name),
+			Mode:   uint32(hdr.Mode) & 07777,
+			Setuid: hdr.Mode&04000 != 0,
+			Setgid: hdr.Mode&02000 != 0,
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			continue
+		case tar.TypeSymlink:
+			f.Link = hdr.Linkname
+		case tar.TypeLink:
+			// Hard links are relative to the archive root, like names:
+			f.Link = tarPath(hdr.Linkname)
+		default:
+			f.Size = uint64(hdr.Size)
+		}
+		files = append(files, f)
+	}
+}
+
+// tarPath converts a name from a .deb's tar archive, like `./usr/bin/vim`, to an absolute path.
+func tarPath(name string) string {
+	return path.Clean("/" + name)
 }
@@ -1,17 +1,25 @@
 package debian_test
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/blakesmith/ar"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/filter"
 	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
 )
 
 func TestPackageParser_PackageFromDeb(t *testing.T) {
@@ -30,6 +38,126 @@ func TestPackageParser_PackageFromDeb(t *testing.T) {
 	assert.Equal(t, "amd64", pkg.Architecture)
 }
 
+const testPostinst = "#!/bin/sh\nset -e\n"
+
+// buildDeb builds a .deb with control and data archives compressed with the given compression.
+func buildDeb(t *testing.T, compression debian.Compression) []byte {
+	t.Helper()
+	buildTar := func(entries ...*tar.Header) []byte {
+		var buf bytes.Buffer
+		w := tar.NewWriter(&buf)
+		for _, hdr := range entries {
+			require.NoError(t, w.WriteHeader(hdr))
+			_, err := w.Write([]byte(strings.Repeat("x", int(hdr.Size))))
+			require.NoError(t, err)
+		}
+		require.NoError(t, w.Close())
+		var compressed bytes.Buffer
+		require.NoError(t, compression.Compress(&compressed, &buf))
+		return compressed.Bytes()
+	}
+
+	var controlTar bytes.Buffer
+	tw := tar.NewWriter(&controlTar)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, f := range []struct {
+		name, content string
+	}{
+		{name: "./postinst", content: testPostinst},
+		{name: "./control", content: "Package: tool\nVersion: 1.0\nArchitecture: amd64\nDescription: a tool\n"},
+		{name: "./md5sums", content: ""},
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(f.content))}))
+		_, err := tw.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	var compressedControl bytes.Buffer
+	require.NoError(t, compression.Compress(&compressedControl, &controlTar))
+
+	var deb bytes.Buffer
+	w := ar.NewWriter(&deb)
+	require.NoError(t, w.WriteGlobalHeader())
+	for _, member := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "debian-binary", data: []byte("2.0\n")},
+		{name: "control.tar" + compression.Extension(), data: compressedControl.Bytes()},
+		{name: "data.tar" + compression.Extension(), data: buildTar(
+			&tar.Header{Name: "./", Typeflag: tar.TypeDir, Mode: 0755},
+			&tar.Header{Name: "./usr/bin/", Typeflag: tar.TypeDir, Mode: 0755},
+			&tar.Header{Name: "./usr/bin/tool", Typeflag: tar.TypeReg, Mode: 04755, Size: 3},
+			&tar.Header{Name: "./usr/bin/tool-alias", Typeflag: tar.TypeSymlink, Mode: 0777, Linkname: "tool"},
+			&tar.Header{Name: "./etc/tool.conf", Typeflag: tar.TypeReg, Mode: 0644, Size: 2},
+		)},
+	} {
+		require.NoError(t, w.WriteHeader(&ar.Header{Name: member.name, Mode: 0644, Size: int64(len(member.data))}))
+		_, err := w.Write(member.data)
+		require.NoError(t, err)
+	}
+	return deb.Bytes()
+}
+
+func TestPackageParser_PackageFromDeb_Compression(t *testing.T) {
+	parser := debian.NewParser(observability.NoopTracer)
+	for _, compression := range []debian.Compression{
+		debian.CompressionNone,
+		debian.CompressionGZIP,
+		debian.CompressionXZ,
+		debian.CompressionZSTD,
+		debian.CompressionLZMA,
+	} {
+		t.Run(string(compression), func(t *testing.T) {
+			pkg, err := parser.PackageFromDeb(context.Background(), bytes.NewReader(buildDeb(t, compression)))
+			require.NoError(t, err)
+			require.NotNil(t, pkg)
+			assert.Equal(t, "tool", pkg.Name)
+			assert.Equal(t, "1.0", pkg.Version)
+			assert.Equal(t, []*hedge.DebianFile{
+				{Path: "/usr/bin/tool", Mode: 04755, Size: 3, Setuid: true},
+				{Path: "/usr/bin/tool-alias", Mode: 0777, Link: "tool"},
+				{Path: "/etc/tool.conf", Mode: 0644, Size: 2},
+			}, pkg.Files)
+			postinstDigest := sha256.Sum256([]byte(testPostinst))
+			assert.Equal(t, map[string]*hedge.DebianScript{
+				"postinst": {Sha256: hex.EncodeToString(postinstDigest[:]), Size: uint64(len(testPostinst))},
+			}, pkg.MaintainerScripts)
+		})
+	}
+}
+
+func TestPackageParser_PackageFromDeb_ContentPolicies(t *testing.T) {
+	pkg, err := debian.NewParser(observability.NoopTracer).PackageFromDeb(context.Background(), bytes.NewReader(buildDeb(t, debian.CompressionXZ)))
+	require.NoError(t, err)
+
+	cases := map[string]bool{
+		`files: [...{setuid: false}]`:                                         false,
+		`files: [...{path: =~"^/(usr|etc)/"}]`:                                true,
+		`files: [...{path: =~"^/usr/"}]`:                                      false,
+		`files: [...{path: "/usr/bin/tool", setuid: true} | {setuid: false}]`: true,
+		`maintainer_scripts: close({})`:                                       false,
+		`maintainer_scripts: close({triggers?: _})`:                           false,
+		`maintainer_scripts: [string]: sha256: "%s"`:                          true,
+		`maintainer_scripts: [string]: sha256: "0000"`:                        false,
+	}
+	postinstDigest := sha256.Sum256([]byte(testPostinst))
+	for policy, expected := range cases {
+		if strings.Contains(policy, "%s") {
+			policy = fmt.Sprintf(policy, hex.EncodeToString(postinstDigest[:]))
+		}
+		t.Run(policy, func(t *testing.T) {
+			pred, err := filter.CuePoliciesToPredicate[*hedge.DebianPackage](map[string]string{
+				"files.cue": policy,
+			}, filter.Config{AnyOf: []string{"files.cue"}})
+			require.NoError(t, err)
+			ok, err := pred(context.Background(), pkg)
+			require.NoError(t, err)
+			assert.Equal(t, expected, ok)
+		})
+	}
+}
+
 func TestParser_Release(t *testing.T) {
 	keyData, err := os.ReadFile("testdata/bullseye_pubkey.txt")
 	require.NoError(t, err)
@@ -0,0 +1,415 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// Reference: https://wiki.debian.org/DebianRepository/Format#Diffs
+
+// PatchNameFormat is the layout of patch names, which are timestamps like the Debian archive.
+const PatchNameFormat = "2006-01-02-1504.05"
+
+const (
+	// pdiffHistory is how many patches are listed in a Packages.diff/Index.
+	// Clients that are further behind download the full Packages file.
+	pdiffHistory = 32
+	// pdiffTTL is how long the history of a Packages file is stored.
+	pdiffTTL = 30 * 24 * time.Hour
+	// maxDiffEdits bounds the cost of a patch. Files that differ by more lines are not patched.
+	maxDiffEdits = 1000
+)
+
+var errTooDifferent = errors.New("files are too different to patch")
+
+// pdiffStore remembers the previous renderings of each Packages file, as patches from one to the next.
+type pdiffStore struct {
+	tracer  trace.Tracer
+	storage cached.ByteStorage
+	now     func() time.Time
+
+	// mu serializes updates to the history within this process.
+	mu sync.Mutex
+}
+
+func newPDiffStore(tracer trace.Tracer, storage cached.ByteStorage) *pdiffStore {
+	return &pdiffStore{
+		tracer:  tracer,
+		storage: cached.WithPrefix[string, []byte]("debian_pdiffs", storage),
+		now:     time.Now,
+	}
+}
+
+// Update records the current rendering of a Packages file, adding a patch from the previous rendering if it changed.
+func (s *pdiffStore) Update(ctx context.Context, repo string, component Component, arch Architecture, packages []byte) (*hedge.DebianPackagesDiffs, error) {
+	ctx, span := s.tracer.Start(ctx, "debian.updatePackagesDiffs", trace.WithAttributes(attrRepository(repo), attrComponent(string(component)), attrArchitecture(arch)))
+	defer span.End()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := pdiffKey(repo, component, arch)
+	diffs, err := s.load(ctx, key)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	digest := sha256.Sum256(packages)
+	if bytes.Equal(diffs.CurrentSha256, digest[:]) {
+		return diffs, nil
+	}
+
+	if diffs.CurrentSha256 != nil {
+		previous, err := s.storage.Get(ctx, key+":current")
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+		var patch *hedge.DebianPackagesDiffs_Patch
+		if previous != nil {
+			patch, err = s.patch(diffs, *previous, packages)
+			if err != nil && !errors.Is(err, errTooDifferent) {
+				return nil, observability.CaptureError(span, err)
+			}
+		}
+		if patch != nil {
+			diffs.Patches = append(diffs.Patches, patch)
+			if n := len(diffs.Patches); n > pdiffHistory {
+				diffs.Patches = diffs.Patches[n-pdiffHistory:]
+			}
+		} else {
+			// Without a patch to the current file, older patches can't reach it:
+			diffs.Patches = nil
+		}
+	}
+	diffs.CurrentSha256 = digest[:]
+	diffs.CurrentSize = uint64(len(packages))
+
+	if err := s.storage.Set(ctx, key+":current", packages, pdiffTTL); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	b, err := proto.Marshal(diffs)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if err := s.storage.Set(ctx, key, b, pdiffTTL); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return diffs, nil
+}
+
+func (s *pdiffStore) patch(diffs *hedge.DebianPackagesDiffs, previous, current []byte) (*hedge.DebianPackagesDiffs_Patch, error) {
+	var script bytes.Buffer
+	if err := WriteEdDiff(&script, previous, current); err != nil {
+		return nil, err
+	}
+	var download bytes.Buffer
+	if err := CompressionGZIP.Compress(&download, bytes.NewReader(script.Bytes())); err != nil {
+		return nil, err
+	}
+
+	// Names must be unique and ordered, even if the clock has not advanced past the latest patch:
+	created := s.now().UTC().Truncate(time.Second)
+	if n := len(diffs.Patches); n > 0 {
+		if latest, err := time.Parse(PatchNameFormat, diffs.Patches[n-1].Name); err == nil && !created.After(latest) {
+			created = latest.Add(time.Second)
+		}
+	}
+	previousDigest := sha256.Sum256(previous)
+	scriptDigest := sha256.Sum256(script.Bytes())
+	return &hedge.DebianPackagesDiffs_Patch{
+		Name:          created.Format(PatchNameFormat),
+		HistorySha256: previousDigest[:],
+		HistorySize:   uint64(len(previous)),
+		PatchSha256:   scriptDigest[:],
+		PatchSize:     uint64(script.Len()),
+		Download:      download.Bytes(),
+	}, nil
+}
+
+// Load returns the history of a Packages file.
+func (s *pdiffStore) Load(ctx context.Context, repo string, component Component, arch Architecture) (*hedge.DebianPackagesDiffs, error) {
+	return s.load(ctx, pdiffKey(repo, component, arch))
+}
+
+func (s *pdiffStore) load(ctx context.Context, key string) (*hedge.DebianPackagesDiffs, error) {
+	var diffs hedge.DebianPackagesDiffs
+	b, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return &diffs, nil
+	}
+	if err := proto.Unmarshal(*b, &diffs); err != nil {
+		return nil, fmt.Errorf("decoding packages diffs: %w", err)
+	}
+	return &diffs, nil
+}
+
+func pdiffKey(repo string, component Component, arch Architecture) string {
+	return fmt.Sprintf("%s:%s/binary-%s", repo, component, arch)
+}
+
+// WriteDiffIndex renders the Packages.diff/Index file of a Packages file's history.
+func WriteDiffIndex(w io.Writer, diffs *hedge.DebianPackagesDiffs) error {
+	history := make([]string, 0, len(diffs.Patches))
+	patches := make([]string, 0, len(diffs.Patches))
+	downloads := make([]string, 0, len(diffs.Patches))
+	for _, p := range diffs.Patches {
+		history = append(history, fmt.Sprintf("%x %d %s", p.HistorySha256, p.HistorySize, p.Name))
+		patches = append(patches, fmt.Sprintf("%x %d %s", p.PatchSha256, p.PatchSize, p.Name))
+		downloadDigest := sha256.Sum256(p.Download)
+		downloads = append(downloads, fmt.Sprintf("%x %d %s.gz", downloadDigest, len(p.Download), p.Name))
+	}
+	return WriteControlFile(w, Paragraph{
+		"SHA256-Current":  fmt.Sprintf("%x %d", diffs.CurrentSha256, diffs.CurrentSize),
+		"SHA256-History":  strings.Join(history, "\n"),
+		"SHA256-Patches":  strings.Join(patches, "\n"),
+		"SHA256-Download": strings.Join(downloads, "\n"),
+	})
+}
+
+// diffIndex returns the digest of a Packages.diff/Index that patches to the rendered Packages file.
+// Returns nil if there are no patches to list, or if the patches lead to another rendering.
+func (h Handler) diffIndex(ctx context.Context, rh *repositoryHandler, rendering *hedge.DebianRendering, component Component, arch Architecture) (*PackagesDigest, error) {
+	diffs, err := h.pdiffs.Load(ctx, rh.name, component, arch)
+	if err != nil {
+		return nil, fmt.Errorf("loading packages diffs: %w", err)
+	}
+	// A stored rendering is served after a policy is reverted, but the patches continue from the rendering before it:
+	packages, ok := rendering.Files[fmt.Sprintf("%s/binary-%s/Packages", component, arch)]
+	if !ok || !bytes.Equal(packages.Sha256Sum, diffs.CurrentSha256) {
+		return nil, nil
+	}
+	if len(diffs.Patches) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiffIndex(&buf, diffs); err != nil {
+		return nil, err
+	}
+	sha := sha256.Sum256(buf.Bytes())
+	md := md5.Sum(buf.Bytes())
+	return &PackagesDigest{
+		Path:   fmt.Sprintf("%s/binary-%s/Packages.diff/Index", component, arch),
+		Size:   buf.Len(),
+		Sha256: sha[:],
+		Md5:    md[:],
+	}, nil
+}
+
+// loadDiffs returns the history of the requested Packages file, or nil if it has no patches.
+// Snapshots never change, so they have no patches.
+func (h Handler) loadDiffs(ctx context.Context, req base.HttpRequest) (*hedge.DebianPackagesDiffs, error) {
+	rh, err := h.repository(ctx, req)
+	if err != nil || rh == nil || rh.snapshot != nil {
+		return nil, err
+	}
+	diffs, err := h.pdiffs.Load(ctx, rh.name, Component(req.PathVars["component"]), Architecture(req.PathVars["arch"]))
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs.Patches) == 0 {
+		return nil, nil
+	}
+	return diffs, nil
+}
+
+// HandleDiffIndex serves the Packages.diff/Index file listed in the Release file, optionally by hash.
+func (h Handler) HandleDiffIndex(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	diffs, err := h.loadDiffs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if diffs == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDiffIndex(&buf, diffs); err != nil {
+		return nil, err
+	}
+	if digest, ok := req.PathVars["digest"]; ok && digest != fmt.Sprintf("%x", sha256.Sum256(buf.Bytes())) {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	return &hedge.HttpResponse{
+		Body: buf.Bytes(),
+	}, nil
+}
+
+// HandleDiffPatch serves a gzipped patch listed in a Packages.diff/Index file.
+func (h Handler) HandleDiffPatch(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	diffs, err := h.loadDiffs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if diffs != nil {
+		for _, p := range diffs.Patches {
+			if p.Name == req.PathVars["patch"] {
+				return &hedge.HttpResponse{
+					Body: p.Download,
+				}, nil
+			}
+		}
+	}
+	return &hedge.HttpResponse{
+		StatusCode: http.StatusNotFound,
+	}, nil
+}
+
+// WriteEdDiff writes an ed script that transforms one file into another, like `diff --ed`.
+// Commands are written from the end of the file to the start, so line numbers are not changed by earlier commands.
+func WriteEdDiff(out io.Writer, from, to []byte) error {
+	a, b := splitLines(from), splitLines(to)
+	hunks, err := diffLines(a, b)
+	if err != nil {
+		return err
+	}
+	var w bytes.Buffer
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		switch {
+		case h.fromStart == h.fromEnd:
+			fmt.Fprintf(&w, "%da\n", h.fromStart)
+		case h.toStart == h.toEnd:
+			fmt.Fprintf(&w, "%sd\n", edRange(h.fromStart, h.fromEnd))
+			continue
+		default:
+			fmt.Fprintf(&w, "%sc\n", edRange(h.fromStart, h.fromEnd))
+		}
+		for _, line := range b[h.toStart:h.toEnd] {
+			w.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				w.WriteString("\n")
+			}
+		}
+		w.WriteString(".\n")
+	}
+	_, err = out.Write(w.Bytes())
+	return err
+}
+
+func edRange(start, end int) string {
+	if end-start == 1 {
+		return fmt.Sprintf("%d", end)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end)
+}
+
+func splitLines(b []byte) []string {
+	lines := strings.SplitAfter(string(b), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// hunk replaces lines [fromStart, fromEnd) of one file with lines [toStart, toEnd) of another.
+type hunk struct {
+	fromStart, fromEnd int
+	toStart, toEnd     int
+}
+
+// diffLines finds the hunks that transform a into b, using Myers' algorithm.
+// Reference: http://www.xmailserver.org/diff2.pdf
+func diffLines(a, b []string) ([]hunk, error) {
+	// Trim the common prefix and suffix, which are most of a Packages file:
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	n, m := len(a), len(b)
+
+	// v[k] is the furthest x reached on diagonal k, offset by maxD. trace[d] is v before the d-th edit, limited to the
+	// diagonals that were reachable.
+	maxD := n + m
+	v := make([]int, 2*maxD+2)
+	var trace [][]int
+	found := maxD == 0
+	for d := 0; d <= maxD && !found; d++ {
+		if d > maxDiffEdits {
+			return nil, errTooDifferent
+		}
+		trace = append(trace, append([]int(nil), v[maxD-d:maxD+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[maxD+k-1] < v[maxD+k+1]) {
+				x = v[maxD+k+1]
+			} else {
+				x = v[maxD+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[maxD+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+
+	// Walk back through the trace, collecting hunks from the end:
+	var hunks []hunk
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		vd := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && vd[d+k-1] < vd[d+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := vd[d+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+		}
+		// The edit between (prevX, prevY) and (x, y) is a deletion or insertion of one line:
+		if n := len(hunks); n > 0 && hunks[n-1].fromStart == x && hunks[n-1].toStart == y {
+			hunks[n-1].fromStart, hunks[n-1].toStart = prevX, prevY
+		} else {
+			hunks = append(hunks, hunk{fromStart: prevX, fromEnd: x, toStart: prevY, toEnd: y})
+		}
+		x, y = prevX, prevY
+	}
+
+	// Reverse into file order, restoring the trimmed prefix:
+	ret := make([]hunk, 0, len(hunks))
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		ret = append(ret, hunk{
+			fromStart: h.fromStart + prefix, fromEnd: h.fromEnd + prefix,
+			toStart: h.toStart + prefix, toEnd: h.toEnd + prefix,
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,162 @@
+package debian_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+// applyEd applies an ed script of `a`, `c` and `d` commands, like apt's rred.
+func applyEd(t *testing.T, in []byte, script []byte) []byte {
+	t.Helper()
+	lines := strings.SplitAfter(string(in), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(script))
+	for scanner.Scan() {
+		cmd := scanner.Text()
+		op := cmd[len(cmd)-1]
+		startRaw, endRaw, ok := strings.Cut(cmd[:len(cmd)-1], ",")
+		start, err := strconv.Atoi(startRaw)
+		require.NoError(t, err)
+		end := start
+		if ok {
+			end, err = strconv.Atoi(endRaw)
+			require.NoError(t, err)
+		}
+
+		var added []string
+		if op == 'a' || op == 'c' {
+			for scanner.Scan() && scanner.Text() != "." {
+				added = append(added, scanner.Text()+"\n")
+			}
+		}
+		switch op {
+		case 'a':
+			lines = append(lines[:start], append(added, lines[start:]...)...)
+		case 'c', 'd':
+			lines = append(lines[:start-1], append(added, lines[end:]...)...)
+		default:
+			t.Fatalf("unknown command %q", cmd)
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return []byte(strings.Join(lines, ""))
+}
+
+func TestWriteEdDiff(t *testing.T) {
+	cases := map[string]struct {
+		from, to string
+	}{
+		"identical":  {from: "a\nb\nc\n", to: "a\nb\nc\n"},
+		"append":     {from: "a\nb\n", to: "a\nb\nc\nd\n"},
+		"prepend":    {from: "b\nc\n", to: "a\nb\nc\n"},
+		"delete":     {from: "a\nb\nc\nd\n", to: "a\nd\n"},
+		"change":     {from: "a\nb\nc\n", to: "a\nx\ny\nc\n"},
+		"from empty": {from: "", to: "a\nb\n"},
+		"to empty":   {from: "a\nb\n", to: ""},
+		"many hunks": {from: "a\nb\nc\nd\ne\nf\ng\n", to: "a\nB\nc\ne\nf\nF\ng\nh\n"},
+		"repeated":   {from: "a\n\na\n\na\n", to: "a\n\nb\n\na\n\na\n"},
+	}
+	for label, tc := range cases {
+		t.Run(label, func(t *testing.T) {
+			var script bytes.Buffer
+			require.NoError(t, debian.WriteEdDiff(&script, []byte(tc.from), []byte(tc.to)))
+			assert.Equal(t, tc.to, string(applyEd(t, []byte(tc.from), script.Bytes())))
+			if tc.from == tc.to {
+				assert.Empty(t, script.String())
+			}
+		})
+	}
+}
+
+func TestHandler_PackagesDiff(t *testing.T) {
+	policies := map[string]string{"everything.cue": `name: string`}
+	storage := cached.InMemory[string, []byte]()
+
+	before := newTestMirror(t)
+	h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(before.URL, before.PubKey, "everything.cue"), policies)
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+	assert.NotContains(t, release.Digests, "main/binary-amd64/Packages.diff/Index")
+	res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	oldPackages := res.Body.Bytes()
+	res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages.diff/Index")
+	assert.Equal(t, http.StatusNotFound, res.Code)
+
+	// Upstream publishes a new version of testpkg:
+	after := newTestMirror(t)
+	after.SetPackages(t, "main", bytes.Replace(after.packages["main"], []byte("Version: 1.2.3\n"), []byte("Version: 1.2.4\n"), 1))
+	h = newTestHandlerWithStorage(t, storage, testRepositoryConfig(after.URL, after.PubKey, "everything.cue"), policies)
+	release = getRelease(t, h, "/debian/dists/test/InRelease")
+	res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, res.Code)
+	newPackages := res.Body.Bytes()
+	require.NotEqual(t, oldPackages, newPackages)
+
+	// The Index is listed in the Release, and describes the patch from the old Packages file to the new one:
+	digest, ok := release.Digests["main/binary-amd64/Packages.diff/Index"]
+	require.True(t, ok)
+	assert.NotContains(t, release.Digests, "contrib/binary-amd64/Packages.diff/Index")
+	res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages.diff/Index")
+	require.Equal(t, http.StatusOK, res.Code)
+	index := res.Body.Bytes()
+	indexDigest := sha256.Sum256(index)
+	assert.Equal(t, digest.Sha256Sum, indexDigest[:])
+	res = get(t, h, fmt.Sprintf("/debian/dists/test/main/binary-amd64/Packages.diff/by-hash/SHA256/%x", indexDigest))
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, index, res.Body.Bytes())
+
+	graphs, err := debian.ParseControlFile(bytes.NewReader(index))
+	require.NoError(t, err)
+	require.Len(t, graphs, 1)
+	graph := graphs[0]
+	assert.Equal(t, fmt.Sprintf("%x %d", sha256.Sum256(newPackages), len(newPackages)), graph["SHA256-Current"])
+	history := strings.Fields(graph["SHA256-History"])
+	require.Len(t, history, 3)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(oldPackages)), history[0])
+	assert.Equal(t, strconv.Itoa(len(oldPackages)), history[1])
+	patchName := history[2]
+	download := strings.Fields(graph["SHA256-Download"])
+	require.Len(t, download, 3)
+	assert.Equal(t, patchName+".gz", download[2])
+	patches := strings.Fields(graph["SHA256-Patches"])
+	require.Len(t, patches, 3)
+
+	res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages.diff/"+patchName+".gz")
+	require.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, download[0], fmt.Sprintf("%x", sha256.Sum256(res.Body.Bytes())))
+	var patch bytes.Buffer
+	require.NoError(t, debian.CompressionGZIP.Decompress(&patch, res.Body))
+	assert.Equal(t, patches[0], fmt.Sprintf("%x", sha256.Sum256(patch.Bytes())))
+	assert.Equal(t, newPackages, applyEd(t, oldPackages, patch.Bytes()))
+
+	t.Run("unknown patch", func(t *testing.T) {
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages.diff/2000-01-01-0000.00.gz")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		res := get(t, h, "/debian/snapshots/test")
+		require.Equal(t, http.StatusOK, res.Code)
+		ids := strings.Fields(res.Body.String())
+		require.NotEmpty(t, ids)
+		// Snapshots never change, so they have no patches:
+		release := getRelease(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/InRelease", ids[len(ids)-1]))
+		assert.NotContains(t, release.Digests, "main/binary-amd64/Packages.diff/Index")
+		res = get(t, h, fmt.Sprintf("/debian/snapshots/test/%s/dists/test/main/binary-amd64/Packages.diff/Index", ids[len(ids)-1]))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -0,0 +1,159 @@
+package debian
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// pinJobs is how many batches of pool files are pinned at once.
+const pinJobs = 4
+
+// pinnedFiles stores the verified pool files of versions hedge serves after upstream may have removed them, like
+// retained versions. Files are pinned by digest, and a pin is only ever extended.
+type pinnedFiles struct {
+	tracer trace.Tracer
+	// blobs are verified pool files by digest, shared with the pool proxy.
+	blobs cached.ByteStorage
+	// until records when each pinned file expires, by digest.
+	until cached.ByteStorage
+	now   func() time.Time
+
+	// jobs limits the batches pinned at once, inflight skips batches that are already being pinned.
+	jobs     chan struct{}
+	inflight sync.Map
+}
+
+func newPinnedFiles(tracer trace.Tracer, storage, blobs cached.ByteStorage) *pinnedFiles {
+	return &pinnedFiles{
+		tracer: tracer,
+		blobs:  blobs,
+		until:  cached.WithPrefix[string, []byte]("debian_pinned", storage),
+		now:    time.Now,
+		jobs:   make(chan struct{}, pinJobs),
+	}
+}
+
+// Until returns when the pool file with a digest stops being pinned, or zero if it isn't pinned.
+func (p *pinnedFiles) Until(ctx context.Context, digest []byte) (time.Time, error) {
+	b, err := p.until.Get(ctx, hex.EncodeToString(digest))
+	if err != nil || b == nil {
+		return time.Time{}, err
+	}
+	var until timestamppb.Timestamp
+	if err := proto.Unmarshal(*b, &until); err != nil {
+		return time.Time{}, fmt.Errorf("decoding pinned pool file: %w", err)
+	}
+	return until.AsTime(), nil
+}
+
+// Pin stores a verified pool file for at least ttl. Files already pinned for longer keep their pin.
+func (p *pinnedFiles) Pin(ctx context.Context, digest, b []byte, ttl time.Duration) error {
+	until := p.now().Add(ttl)
+	if pinned, err := p.Until(ctx, digest); err != nil {
+		return err
+	} else if !pinned.Before(until) {
+		return nil
+	}
+
+	key := hex.EncodeToString(digest)
+	if err := p.blobs.Set(ctx, key, b, ttl); err != nil {
+		return err
+	}
+	marker, err := proto.Marshal(timestamppb.New(until))
+	if err != nil {
+		return err
+	}
+	return p.until.Set(ctx, key, marker, ttl)
+}
+
+// Background runs a batch of pins without blocking the caller. Batches with the same key are skipped while one is
+// running, the caller tries again the next time it sees the files unpinned.
+func (p *pinnedFiles) Background(key string, fn func(context.Context) error) {
+	if _, running := p.inflight.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	go func() {
+		defer p.inflight.Delete(key)
+		p.jobs <- struct{}{}
+		defer func() { <-p.jobs }()
+
+		ctx, span := p.tracer.Start(context.Background(), "debian.pinPoolFiles", trace.WithAttributes(attribute.String("pin", key)))
+		defer span.End()
+		if err := fn(ctx); err != nil {
+			_ = observability.CaptureError(span, err)
+		}
+	}()
+}
+
+// pinPoolFiles pins the pool files of an index's packages in the background, checked against the repository's
+// policies like the pool proxy does. Packages have upstream filenames. Files already pinned for half of ttl are left
+// alone, files that fail are tried again by the next call.
+func (h Handler) pinPoolFiles(rh *repositoryHandler, args LoadPackagesArgs, pkgs []*hedge.DebianPackage, ttl time.Duration) {
+	if len(pkgs) == 0 {
+		return
+	}
+	key := fmt.Sprintf("%s:%s:%s", rh.name, snapshotPackagesKey(args.Component, args.Architecture), ttl)
+	h.pins.Background(key, func(ctx context.Context) error {
+		var failed int
+		var lastErr error
+		for _, pkg := range pkgs {
+			if err := h.pinPoolFile(ctx, rh, args.Release, args.Component, pkg, ttl); err != nil {
+				failed++
+				lastErr = fmt.Errorf("pinning %s: %w", pkg.Filename, err)
+			}
+		}
+		if lastErr != nil {
+			return fmt.Errorf("%d of %d pool files not pinned, last: %w", failed, len(pkgs), lastErr)
+		}
+		return nil
+	})
+}
+
+func (h Handler) pinPoolFile(ctx context.Context, rh *repositoryHandler, release *hedge.DebianRelease, component Component, pkg *hedge.DebianPackage, ttl time.Duration) error {
+	if until, err := h.pins.Until(ctx, pkg.Sha256); err != nil {
+		return err
+	} else if until.After(h.now().Add(ttl / 2)) {
+		return nil
+	}
+
+	pred := rh.policy(component, pkg.Filename)
+	byPolicy, err := pred(ctx, pkg)
+	if err != nil {
+		return err
+	}
+	args := LoadPoolFileArgs{
+		Release:  release,
+		Filename: pkg.Filename,
+		Size:     pkg.Size,
+		Sha256:   pkg.Sha256,
+	}
+	var b []byte
+	load := func() ([]byte, error) {
+		if b != nil {
+			return b, nil
+		}
+		var err error
+		b, _, err = h.loadPoolFile(ctx, rh.pool, args)
+		return b, err
+	}
+	// Files refused by their contents are not pinned. Their parsed contents are stored, so refused files aren't
+	// fetched again every time they are pinned:
+	if allowed, err := h.allowedContents(ctx, pred, rh.contentPolicy, pkg, byPolicy, load); err != nil || !allowed {
+		return err
+	}
+	if _, err := load(); err != nil {
+		return err
+	}
+	return h.pins.Pin(ctx, pkg.Sha256, b, ttl)
+}
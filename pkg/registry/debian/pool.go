@@ -0,0 +1,272 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// blobTTL is how long verified pool files are stored. They are content-addressed, so this can be long.
+const blobTTL = 7 * 24 * time.Hour
+
+// servedFromPool rewrites the Filename of packages to the repository's pool, so apt fetches them through hedge.
+// Upstream files like `pool/main/v/vim/vim_8.2_amd64.deb` are served as `dists/{repo}/pool/main/v/vim/vim_8.2_amd64.deb`.
+func servedFromPool(repo string, wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	prefix := path.Join("dists", repo)
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		pkgs, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		served := make([]*hedge.DebianPackage, 0, len(pkgs.Packages))
+		for _, pkg := range pkgs.Packages {
+			pkg := proto.Clone(pkg).(*hedge.DebianPackage)
+			pkg.Filename = path.Join(prefix, pkg.Filename)
+			served = append(served, pkg)
+		}
+		return &hedge.DebianPackages{Packages: served}, nil
+	}
+}
+
+func (h Handler) HandlePool(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh, err := h.repository(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rh == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	upstreamFilename := path.Join("pool", req.PathVars["path"])
+	filename := path.Join("dists", rh.name, upstreamFilename)
+
+	release, err := h.loadRelease(ctx, rh)
+	if err != nil {
+		return nil, err
+	}
+
+	// The file must be in the filtered index, which provides the expected digest:
+	pkg, component, err := findPackage(ctx, rh.packages, release, filename)
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		// Source packages are verified against the checksums of the filtered Sources index:
+		srcFile, err := findSourceFile(ctx, rh.sources, release, filename)
+		if err != nil {
+			return nil, err
+		}
+		if srcFile != nil {
+			args := LoadPoolFileArgs{
+				Release:  release,
+				Filename: upstreamFilename,
+				Size:     srcFile.Size,
+				Sha256:   srcFile.Sha256,
+			}
+			b, fetched, err := h.loadPoolFile(ctx, rh.pool, args)
+			if err != nil {
+				return nil, err
+			}
+			if fetched {
+				if err := h.storePoolFile(ctx, args, b); err != nil {
+					return nil, err
+				}
+			}
+			return &hedge.HttpResponse{
+				Body: b,
+			}, nil
+		}
+
+		// Distinguish files that were refused by policy from files that don't exist:
+		upstream, _, err := findPackage(ctx, rh.upstream, release, upstreamFilename)
+		if err != nil {
+			return nil, err
+		}
+		upstreamSrcFile, err := findSourceFile(ctx, rh.upstreamSources, release, upstreamFilename)
+		if err != nil {
+			return nil, err
+		}
+		if upstream != nil || upstreamSrcFile != nil {
+			return &hedge.HttpResponse{
+				StatusCode: http.StatusForbidden,
+			}, nil
+		}
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+
+	// Check the package by name and version before anything is fetched. Packages in the index that the policy did
+	// not allow, like dependencies, are only checked by content policies:
+	pred := rh.policy(component, upstreamFilename)
+	byPolicy, err := pred(ctx, pkg)
+	if err != nil {
+		return nil, err
+	}
+	args := LoadPoolFileArgs{
+		Release:  release,
+		Filename: upstreamFilename,
+		Size:     pkg.Size,
+		Sha256:   pkg.Sha256,
+	}
+	b, fetched, err := h.loadPoolFile(ctx, rh.pool, args)
+	if err != nil {
+		return nil, err
+	}
+	// Files refused by their contents are not stored:
+	if allowed, err := h.allowedContents(ctx, pred, rh.contentPolicy, pkg, byPolicy, loadedFile(b)); err != nil {
+		return nil, err
+	} else if !allowed {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusForbidden,
+		}, nil
+	}
+	if fetched {
+		if err := h.storePoolFile(ctx, args, b); err != nil {
+			return nil, err
+		}
+	}
+	return &hedge.HttpResponse{
+		ContentType: "application/vnd.debian.binary-package",
+		Body:        b,
+	}, nil
+}
+
+func findPackage(ctx context.Context, packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages], release *hedge.DebianRelease, filename string) (*hedge.DebianPackage, Component, error) {
+	for _, c := range release.Components {
+		for _, a := range release.Architectures {
+			pkgs, err := packages(ctx, LoadPackagesArgs{
+				Release:      release,
+				Component:    Component(c),
+				Architecture: Architecture(a),
+			})
+			if err != nil {
+				return nil, "", err
+			}
+			for _, pkg := range pkgs.Packages {
+				if pkg.Filename == filename {
+					return pkg, Component(c), nil
+				}
+			}
+		}
+	}
+	return nil, "", nil
+}
+
+// loadPoolFile returns a verified pool file, from content-addressed storage if possible. Files fetched from the pool
+// are not stored, so they can be checked first: fetched reports whether the caller should storePoolFile.
+func (h Handler) loadPoolFile(ctx context.Context, pool PoolLoader, args LoadPoolFileArgs) (b []byte, fetched bool, err error) {
+	ctx, span := h.tracer.Start(ctx, "debian.loadPoolFile", trace.WithAttributes(attrFilename(args.Filename)))
+	defer span.End()
+
+	key := hex.EncodeToString(args.Sha256)
+	if stored, err := h.blobs.Get(ctx, key); err != nil {
+		return nil, false, observability.CaptureError(span, err)
+	} else if stored != nil {
+		// Storage is shared, so verify on the way out too:
+		if err := verifyFile(*stored, args.Size, args.Sha256); err == nil {
+			span.SetAttributes(observability.CacheHit(true))
+			return *stored, false, nil
+		}
+	}
+	span.SetAttributes(observability.CacheHit(false))
+
+	b, err = pool.LoadPoolFile(ctx, args)
+	if err != nil {
+		return nil, false, observability.CaptureError(span, err)
+	}
+	return b, true, nil
+}
+
+// loadedFile is the loader of a pool file that is already loaded.
+func loadedFile(b []byte) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return b, nil
+	}
+}
+
+// storePoolFile stores a verified pool file by digest.
+func (h Handler) storePoolFile(ctx context.Context, args LoadPoolFileArgs, b []byte) error {
+	return h.blobs.Set(ctx, hex.EncodeToString(args.Sha256), b, blobTTL)
+}
+
+// allowedContents applies policies to a package with its contents, which are only known once the .deb is fetched.
+// Packages allowed by pred without their contents (byPolicy), must still be allowed with them. Packages that pred
+// did not allow, like dependencies, are only checked by contentPred. contentPred may be nil. deb is only loaded if
+// the contents are needed and weren't parsed before.
+func (h Handler) allowedContents(ctx context.Context, pred, contentPred filter.Predicate[*hedge.DebianPackage], pkg *hedge.DebianPackage, byPolicy bool, deb func() ([]byte, error)) (bool, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.allowedContents", trace.WithAttributes(attrFilename(pkg.Filename)))
+	defer span.End()
+
+	if !byPolicy && contentPred == nil {
+		return true, nil
+	}
+	contents, err := h.debContents(ctx, pkg.Sha256, deb)
+	if err != nil {
+		return false, observability.CaptureError(span, err)
+	}
+	withContents := proto.Clone(pkg).(*hedge.DebianPackage)
+	withContents.Files = contents.Files
+	withContents.MaintainerScripts = contents.MaintainerScripts
+	if byPolicy {
+		if ok, err := pred(ctx, withContents); err != nil {
+			return false, observability.CaptureError(span, err)
+		} else if !ok {
+			return false, nil
+		}
+	}
+	if contentPred != nil {
+		ok, err := contentPred(ctx, withContents)
+		if err != nil {
+			return false, observability.CaptureError(span, err)
+		}
+		return ok, nil
+	}
+	return true, nil
+}
+
+// debContents parses a verified .deb, with results stored by digest.
+func (h Handler) debContents(ctx context.Context, digest []byte, deb func() ([]byte, error)) (*hedge.DebianPackage, error) {
+	key := hex.EncodeToString(digest)
+	if stored, err := h.contents.Get(ctx, key); err != nil {
+		return nil, err
+	} else if stored != nil {
+		var pkg hedge.DebianPackage
+		if err := proto.Unmarshal(*stored, &pkg); err == nil {
+			return &pkg, nil
+		}
+	}
+
+	b, err := deb()
+	if err != nil {
+		return nil, err
+	}
+	pkg, err := h.parser.PackageFromDeb(ctx, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if pkg == nil {
+		return nil, fmt.Errorf("no control file found")
+	}
+	parsed, err := proto.Marshal(pkg)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.contents.Set(ctx, key, parsed, blobTTL); err != nil {
+		return nil, err
+	}
+	return pkg, nil
+}
@@ -0,0 +1,173 @@
+package debian
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// Reference: https://www.debian.org/doc/debian-policy/ch-relationships.html#syntax-of-relationship-fields
+
+// versionOperators are the relations between versions, longest first so `<<` is not read as `<`.
+var versionOperators = []string{"<<", "<=", ">=", ">>", "=", "<", ">"}
+
+// ParseDependencies parses a relationship field like `libc6 (>= 2.17), default-mta | mail-transport-agent`.
+func ParseDependencies(field string) ([]*hedge.DebianDependency, error) {
+	var deps []*hedge.DebianDependency
+	for rest, more := field, true; more; {
+		var d string
+		d, rest, more = strings.Cut(rest, ",")
+		if trimRelationSpace(d) == "" {
+			continue
+		}
+		dep := &hedge.DebianDependency{Alternatives: make([]*hedge.DebianRelation, 0, strings.Count(d, "|")+1)}
+		for alts, moreAlts := d, true; moreAlts; {
+			var alt string
+			alt, alts, moreAlts = strings.Cut(alts, "|")
+			rel, err := ParseRelation(alt)
+			if err != nil {
+				return nil, err
+			}
+			dep.Alternatives = append(dep.Alternatives, rel)
+		}
+		if deps == nil {
+			deps = make([]*hedge.DebianDependency, 0, strings.Count(rest, ",")+1)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// ParseRelations parses a relationship field that does not allow alternatives, like Provides.
+func ParseRelations(field string) ([]*hedge.DebianRelation, error) {
+	var rels []*hedge.DebianRelation
+	for rest, more := field, true; more; {
+		var r string
+		r, rest, more = strings.Cut(rest, ",")
+		if trimRelationSpace(r) == "" {
+			continue
+		}
+		rel, err := ParseRelation(r)
+		if err != nil {
+			return nil, err
+		}
+		rels = append(rels, rel)
+	}
+	return rels, nil
+}
+
+// ParseRelation parses a single relation like `libc6:any (>= 2.17)`.
+// Relations are parsed by hand, as every package has several and a regular expression is too slow for large indices.
+func ParseRelation(s string) (*hedge.DebianRelation, error) {
+	var rel hedge.DebianRelation
+	rest := strings.TrimSpace(s)
+	rel.Name, rest = cutRelationToken(rest, ":([")
+	if rel.Name == "" {
+		return nil, invalidRelation(s)
+	}
+	if strings.HasPrefix(rest, ":") {
+		if rel.ArchQualifier, rest = cutRelationToken(rest[1:], "(["); rel.ArchQualifier == "" {
+			return nil, invalidRelation(s)
+		}
+	}
+
+	rest = trimRelationSpace(rest)
+	if strings.HasPrefix(rest, "(") {
+		rest = trimRelationSpace(rest[1:])
+		for _, op := range versionOperators {
+			if strings.HasPrefix(rest, op) {
+				rel.VersionOperator = op
+				break
+			}
+		}
+		if rel.VersionOperator == "" {
+			return nil, invalidRelation(s)
+		}
+		rel.Version, rest = cutRelationToken(trimRelationSpace(rest[len(rel.VersionOperator):]), ")")
+		rest = trimRelationSpace(rest)
+		if rel.Version == "" || !strings.HasPrefix(rest, ")") {
+			return nil, invalidRelation(s)
+		}
+		rest = trimRelationSpace(rest[1:])
+	}
+
+	if strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, invalidRelation(s)
+		}
+		if archs := rest[1:end]; archs != "" {
+			rel.Architectures = strings.Fields(archs)
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" {
+		return nil, invalidRelation(s)
+	}
+	return &rel, nil
+}
+
+func invalidRelation(s string) error {
+	return fmt.Errorf("invalid relation: %q", s)
+}
+
+func isRelationSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func trimRelationSpace(s string) string {
+	for len(s) > 0 && isRelationSpace(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+// cutRelationToken splits s before the first whitespace or stop character.
+func cutRelationToken(s, stops string) (string, string) {
+	for i := 0; i < len(s); i++ {
+		if isRelationSpace(s[i]) || strings.IndexByte(stops, s[i]) >= 0 {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+// FormatDependencies is the inverse of ParseDependencies.
+func FormatDependencies(deps []*hedge.DebianDependency) string {
+	formatted := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		alts := make([]string, 0, len(dep.Alternatives))
+		for _, alt := range dep.Alternatives {
+			alts = append(alts, FormatRelation(alt))
+		}
+		formatted = append(formatted, strings.Join(alts, " | "))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// FormatRelations is the inverse of ParseRelations.
+func FormatRelations(rels []*hedge.DebianRelation) string {
+	formatted := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		formatted = append(formatted, FormatRelation(rel))
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// FormatRelation is the inverse of ParseRelation.
+func FormatRelation(rel *hedge.DebianRelation) string {
+	var sb strings.Builder
+	sb.WriteString(rel.Name)
+	if rel.ArchQualifier != "" {
+		sb.WriteString(":")
+		sb.WriteString(rel.ArchQualifier)
+	}
+	if rel.VersionOperator != "" {
+		fmt.Fprintf(&sb, " (%s %s)", rel.VersionOperator, rel.Version)
+	}
+	if len(rel.Architectures) > 0 {
+		fmt.Fprintf(&sb, " [%s]", strings.Join(rel.Architectures, " "))
+	}
+	return sb.String()
+}
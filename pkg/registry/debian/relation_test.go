@@ -0,0 +1,68 @@
+package debian_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+func TestParseDependencies(t *testing.T) {
+	cases := map[string][]*hedge.DebianDependency{
+		"": nil,
+		"libc6": {
+			{Alternatives: []*hedge.DebianRelation{{Name: "libc6"}}},
+		},
+		"libc6 (>= 2.17), libstdc++6 (>= 5)": {
+			{Alternatives: []*hedge.DebianRelation{{Name: "libc6", VersionOperator: ">=", Version: "2.17"}}},
+			{Alternatives: []*hedge.DebianRelation{{Name: "libstdc++6", VersionOperator: ">=", Version: "5"}}},
+		},
+		"default-mta | mail-transport-agent": {
+			{Alternatives: []*hedge.DebianRelation{{Name: "default-mta"}, {Name: "mail-transport-agent"}}},
+		},
+		"python3:any (<< 3.10), zlib1g (= 1:1.2.11.dfsg-2)": {
+			{Alternatives: []*hedge.DebianRelation{{Name: "python3", ArchQualifier: "any", VersionOperator: "<<", Version: "3.10"}}},
+			{Alternatives: []*hedge.DebianRelation{{Name: "zlib1g", VersionOperator: "=", Version: "1:1.2.11.dfsg-2"}}},
+		},
+		"libc6-dev [amd64 i386] | libc6.1-dev [!amd64 !i386]": {
+			{Alternatives: []*hedge.DebianRelation{
+				{Name: "libc6-dev", Architectures: []string{"amd64", "i386"}},
+				{Name: "libc6.1-dev", Architectures: []string{"!amd64", "!i386"}},
+			}},
+		},
+	}
+
+	for field, expected := range cases {
+		t.Run(field, func(t *testing.T) {
+			deps, err := debian.ParseDependencies(field)
+			require.NoError(t, err)
+			assert.Equal(t, expected, deps)
+			assert.Equal(t, field, debian.FormatDependencies(deps))
+		})
+	}
+}
+
+func TestParseDependencies_Whitespace(t *testing.T) {
+	deps, err := debian.ParseDependencies(" libc6(>=2.17) ,\n libx11-6|libxxf86vm1,")
+	require.NoError(t, err)
+	assert.Equal(t, "libc6 (>= 2.17), libx11-6 | libxxf86vm1", debian.FormatDependencies(deps))
+}
+
+func TestParseRelations(t *testing.T) {
+	rels, err := debian.ParseRelations("mail-transport-agent, libfoo-abi-1 (= 1.0)")
+	require.NoError(t, err)
+	assert.Equal(t, []*hedge.DebianRelation{
+		{Name: "mail-transport-agent"},
+		{Name: "libfoo-abi-1", VersionOperator: "=", Version: "1.0"},
+	}, rels)
+	assert.Equal(t, "mail-transport-agent, libfoo-abi-1 (= 1.0)", debian.FormatRelations(rels))
+}
+
+func TestParseRelation_Invalid(t *testing.T) {
+	for _, s := range []string{"libc6 (~ 2.17)", "libc6 (>= 2.17", "(>= 2.17)"} {
+		_, err := debian.ParseRelation(s)
+		assert.Error(t, err, s)
+	}
+}
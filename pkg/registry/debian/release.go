@@ -28,7 +28,7 @@ type Component string
 
 func ParagraphFromRelease(r *hedge.DebianRelease) (Paragraph, error) {
 	graph := Paragraph{
-		"Acquire-By-Hash":                 strconv.FormatBool(r.AcquireByHash),
+		"Acquire-By-Hash":                 formatYesNo(r.AcquireByHash),
 		"Architectures":                   strings.Join(r.Architectures, " "),
 		"Changelogs":                      r.Changelogs,
 		"Codename":                        r.Codename,
@@ -36,14 +36,39 @@ func ParagraphFromRelease(r *hedge.DebianRelease) (Paragraph, error) {
 		"Date":                            r.Date.AsTime().Format(time.RFC1123),
 		"Description":                     r.Description,
 		"Label":                           r.Label,
-		"No-Support-for-Architecture-all": strconv.FormatBool(r.NoSupportForArchitectureAll),
+		"No-Support-for-Architecture-all": formatNoSupportForArchitectureAll(r),
 		"Origin":                          r.Origin,
 		"Suite":                           r.Suite,
 		"Version":                         r.Version,
 	}
+	if r.ValidUntil != nil {
+		graph["Valid-Until"] = r.ValidUntil.AsTime().Format(time.RFC1123)
+	}
+	// Known fields take precedence over extra fields:
+	for k, v := range r.ExtraFields {
+		if _, ok := graph[k]; !ok {
+			graph[k] = v
+		}
+	}
 	return graph, nil
 }
 
+func formatYesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// formatNoSupportForArchitectureAll declares that every architecture's Packages index has the `all` packages.
+// It is only meaningful if `all` is listed as an architecture.
+func formatNoSupportForArchitectureAll(r *hedge.DebianRelease) string {
+	if r.NoSupportForArchitectureAll && contains(r.Architectures, string(ArchitectureAll)) {
+		return "Packages"
+	}
+	return ""
+}
+
 func ReleaseFromParagraph(graph Paragraph) (*hedge.DebianRelease, error) {
 	ret := hedge.DebianRelease{
 		AcquireByHash: graph["Acquire-By-Hash"] == "yes",
@@ -70,18 +95,29 @@ func ReleaseFromParagraph(graph Paragraph) (*hedge.DebianRelease, error) {
 			ret.Description = v
 		case "Label":
 			ret.Label = v
-		case "MD5Sum", "SHA256":
-			// skipped, as these are calculated below
+		case "MD5Sum", "SHA1", "SHA256", "SHA512":
+			// skipped, as these are calculated below and rendered by WriteReleaseFile
+		case "Signed-By":
+			// skipped, as hedge signs with its own key
 		case "No-Support-for-Architecture-all":
-			ret.NoSupportForArchitectureAll = v == "yes"
+			ret.NoSupportForArchitectureAll = v == "Packages"
 		case "Origin":
 			ret.Origin = v
 		case "Suite":
 			ret.Suite = v
 		case "Version":
 			ret.Version = v
+		case "Valid-Until":
+			t, err := time.Parse(time.RFC1123, v)
+			if err != nil {
+				return nil, fmt.Errorf("parsing valid-until: %w", err)
+			}
+			ret.ValidUntil = timestamppb.New(t)
 		default:
-			return nil, fmt.Errorf("unknown key: %s", k)
+			if ret.ExtraFields == nil {
+				ret.ExtraFields = map[string]string{}
+			}
+			ret.ExtraFields[k] = v
 		}
 	}
 
@@ -140,7 +176,9 @@ func parseDigests(graph Paragraph) (map[string]*hedge.DebianRelease_DigestedFile
 	return digests, nil
 }
 
-func WriteReleaseFile(ctx context.Context, r *hedge.DebianRelease, pkgs map[Architecture][]*hedge.DebianPackage, w io.Writer) error {
+// WriteReleaseFile renders a Release file, with digests of the Packages file for each component and architecture.
+// Digests of other indexed files, like Packages.diff/Index, can be included.
+func WriteReleaseFile(ctx context.Context, r *hedge.DebianRelease, pkgs map[Component]map[Architecture][]*hedge.DebianPackage, w io.Writer, extra ...PackagesDigest) error {
 	// Conver the basic release to a Paragraph:
 	graph, err := ParagraphFromRelease(r)
 	if err != nil {
@@ -148,13 +186,15 @@ func WriteReleaseFile(ctx context.Context, r *hedge.DebianRelease, pkgs map[Arch
 	}
 
 	// Digest and render all Packages files:
-	var pkgDigests []PackagesDigest
-	for arch, packages := range pkgs {
-		digests, err := PackageHashes(ctx, arch, "main", packages...)
-		if err != nil {
-			return fmt.Errorf("calculating package hashes: %w", err)
+	pkgDigests := append([]PackagesDigest(nil), extra...)
+	for component, archPkgs := range pkgs {
+		for arch, packages := range archPkgs {
+			digests, err := PackageHashes(ctx, arch, component, packages...)
+			if err != nil {
+				return fmt.Errorf("calculating package hashes: %w", err)
+			}
+			pkgDigests = append(pkgDigests, digests...)
 		}
-		pkgDigests = append(pkgDigests, digests...)
 	}
 	sort.Slice(pkgDigests, func(i, j int) bool {
 		return pkgDigests[i].Path < pkgDigests[j].Path
@@ -174,6 +214,11 @@ func WriteReleaseFile(ctx context.Context, r *hedge.DebianRelease, pkgs map[Arch
 	return nil
 }
 
+// IndexedCompressions are the variants of each Packages file that are listed in the Release file.
+// XZ compression is supported, but slooooow.
+// Only use XZ if we are rendering the repository to the filesystem for static hosting.
+var IndexedCompressions = []Compression{CompressionNone, CompressionGZIP}
+
 type PackagesDigest struct {
 	Path   string
 	Size   int
@@ -182,22 +227,19 @@ type PackagesDigest struct {
 }
 
 func PackageHashes(ctx context.Context, arch Architecture, component Component, packages ...*hedge.DebianPackage) ([]PackagesDigest, error) {
-	graphs := make([]Paragraph, 0, len(packages))
-	for _, pkg := range packages {
-		graphs = append(graphs, ParagraphFromPackage(pkg))
-	}
 	var buf strings.Builder
-	if err := WriteControlFile(&buf, graphs...); err != nil {
+	if err := writePackagesFile(&buf, packages); err != nil {
 		return nil, err
 	}
-	pkgFile := buf.String()
+	return indexDigests(fmt.Sprintf("%s/binary-%s/Packages", component, arch), buf.String())
+}
 
-	// XZ compression is supported, but slooooow.
-	// Only use XZ if we are rendering the repository to the filesystem for static hosting.
+// indexDigests digests each variant of an index file that is listed in the Release file.
+func indexDigests(path, content string) ([]PackagesDigest, error) {
 	var digests []PackagesDigest
-	for _, compression := range []Compression{CompressionNone, CompressionGZIP} {
+	for _, compression := range IndexedCompressions {
 		var buf bytes.Buffer
-		if err := compression.Compress(&buf, strings.NewReader(pkgFile)); err != nil {
+		if err := compression.Compress(&buf, strings.NewReader(content)); err != nil {
 			return nil, err
 		}
 		sha := sha256.Sum256(buf.Bytes())
@@ -205,7 +247,7 @@ func PackageHashes(ctx context.Context, arch Architecture, component Component,
 		// TODO: should we use MD5? It's nice to have some resistance to SHA-256 attacks... but it is MD5 🤡
 		md := md5.Sum(buf.Bytes())
 		digests = append(digests, PackagesDigest{
-			Path:   fmt.Sprintf("%s/binary-%s/Packages%s", component, arch, compression.Extension()),
+			Path:   path + compression.Extension(),
 			Size:   buf.Len(),
 			Sha256: sha[:],
 			Md5:    md[:],
@@ -1,5 +1,13 @@
 package debian_test
 
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
 // func TestWriteReleaseFile(t *testing.T) {
 // 	rel := debian.Release{
 // 		Origin:   "Debian",
@@ -25,3 +33,22 @@ package debian_test
 //   fe01b3ec9920bbd6fc4b8ed887711edd07a7b0a06605f9cca91f2f147f6ee1eb 50 main/binary-amd64/Packages.gz
 // `, buf.String())
 // }
+
+func TestReleaseFromParagraph_ExtraFields(t *testing.T) {
+	release, err := debian.ReleaseFromParagraph(debian.Paragraph{
+		"Origin":        "Ubuntu",
+		"Architectures": "amd64",
+		"Components":    "main",
+		"Date":          "Thu, 21 Apr 2022 17:16:08 UTC",
+		"Signed-By":     "F6ECB3762474EDA9D21B7022871920D1991BC93C",
+		"SHA512":        " 0123 10 main/binary-amd64/Packages",
+		"X-Custom":      "value",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"X-Custom": "value"}, release.ExtraFields)
+
+	graph, err := debian.ParagraphFromRelease(release)
+	require.NoError(t, err)
+	assert.Equal(t, "value", graph["X-Custom"])
+	assert.Equal(t, "Ubuntu", graph["Origin"])
+}
@@ -6,28 +6,33 @@ import (
 	"context"
 	"crypto/sha256"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
 	"github.com/thepwagner/hedge/proto/hedge/v1"
 	"go.opentelemetry.io/otel/trace"
-	"golang.org/x/sync/errgroup"
 )
 
 type RemoteRepository struct {
-	tracer   trace.Tracer
-	fetchURL cached.Function[string, []byte]
-	parser   Parser
+	tracer     trace.Tracer
+	fetchURL   cached.Function[string, []byte]
+	parser     Parser
+	hedgeDelay time.Duration
+	// dates rejects releases older than one already seen, if set.
+	dates *releaseDates
 }
 
 func NewRemoteRepository(tracer trace.Tracer, fetchURL cached.Function[string, []byte]) *RemoteRepository {
 	return &RemoteRepository{
-		tracer:   tracer,
-		fetchURL: fetchURL,
-		parser:   NewParser(tracer),
+		tracer:     tracer,
+		fetchURL:   fetchURL,
+		parser:     NewParser(tracer),
+		hedgeDelay: mirrorHedgeDelay,
 	}
 }
 
@@ -35,107 +40,230 @@ func (r *RemoteRepository) LoadRelease(ctx context.Context, args LoadReleaseArgs
 	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadRelease")
 	defer span.End()
 
-	u, err := url.JoinPath(args.MirrorURL, "dists", args.Dist, "InRelease")
-	if err != nil {
-		return nil, fmt.Errorf("building URL: %w", err)
-	}
-	b, err := r.fetchURL(ctx, u)
-	if err != nil {
-		return nil, fmt.Errorf("fetching release file: %w", err)
-	}
-
 	key, err := openpgp.ReadArmoredKeyRing(strings.NewReader(args.SigningKey))
 	if err != nil {
-		return nil, fmt.Errorf("reading key: %w", err)
+		return nil, observability.CaptureError(span, fmt.Errorf("reading key: %w", err))
 	}
-	release, err := r.parser.Release(ctx, bytes.NewReader(b), key)
+
+	// Every mirror must provide a release signed by the key:
+	release, mirror, err := fromMirrors(ctx, r.tracer, r.hedgeDelay, args.MirrorURLs, func(ctx context.Context, mirror string) (*hedge.DebianRelease, error) {
+		u, err := url.JoinPath(mirror, "dists", args.Dist, "InRelease")
+		if err != nil {
+			return nil, fmt.Errorf("building URL: %w", err)
+		}
+		b, err := r.fetchURL(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("fetching release file: %w", err)
+		}
+		release, err := r.parser.Release(ctx, bytes.NewReader(b), key)
+		if err != nil {
+			return nil, fmt.Errorf("parsing release file: %w", err)
+		}
+		// Mirrors of an upstream share the signing key, so they share the newest Date:
+		if r.dates != nil {
+			upstream := fmt.Sprintf("%s:%X", args.Dist, key[0].PrimaryKey.Fingerprint)
+			if err := r.dates.Observe(ctx, upstream, release.Date.AsTime()); err != nil {
+				return nil, fmt.Errorf("checking release date: %w", err)
+			}
+		}
+		return release, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("parsing release file: %w", err)
+		return nil, observability.CaptureError(span, err)
 	}
-	release.MirrorUrl = args.MirrorURL
+	release.MirrorUrl = mirror
+	release.MirrorUrls = args.MirrorURLs
 	release.Dist = args.Dist
-	release.Components = []string{"main"}
 
 	if len(args.Architectures) != 0 {
 		release.Architectures = args.Architectures
 	}
+	if len(args.Components) != 0 {
+		release.Components = args.Components
+	}
 	return release, nil
 }
 
 func (r *RemoteRepository) LoadPackages(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
-	arch := args.Architecture
-	release := args.Release
-	components := release.Components
-	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadPackages", trace.WithAttributes(attrArchitecture(arch), attrComponents(components)))
+	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadPackages", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
 	defer span.End()
 
-	eg, ctx := errgroup.WithContext(ctx)
-	eg.SetLimit(4)
-	res := make(chan []*hedge.DebianPackage)
-	for _, c := range components {
-		component := c
-		eg.Go(func() error {
-			ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadPackages.component", trace.WithAttributes(attrComponent(component)))
-			defer span.End()
-
-			// The Release file specifies the expected properties of the Packages file
-			// The Release file's signature was verified, so we trust it.
-			fn := fmt.Sprintf("%s/binary-%s/Packages.gz", component, arch)
-			digest, ok := args.Release.Digests[fn]
-			if !ok {
-				return fmt.Errorf("release is missing %s/%s", component, arch)
-			}
-			u, err := url.JoinPath(release.MirrorUrl, "dists", release.Dist, digest.Path)
-			if err != nil {
-				return fmt.Errorf("building URL: %w", err)
-			}
+	fn := fmt.Sprintf("%s/binary-%s/Packages.gz", args.Component, args.Architecture)
+	if _, ok := args.Release.Digests[fn]; !ok {
+		return nil, observability.CaptureError(span, fmt.Errorf("release is missing %s/%s", args.Component, args.Architecture))
+	}
+	b, err := r.loadIndex(ctx, args.Release, fn)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
 
-			// If the URL is content-addressed, we can cache it ~forever
-			var fetchCtx context.Context
-			if strings.Contains(digest.Path, "/by-hash/") {
-				fetchCtx = cached.For(ctx, 7*24*time.Hour)
-			} else {
-				fetchCtx = ctx
-			}
-			b, err := r.fetchURL(fetchCtx, u)
-			if err != nil {
-				return fmt.Errorf("fetching release file: %w", err)
-			}
+	// Parse packages from verified file:
+	gzr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	pkgs, err := r.parser.Packages(ctx, gzr)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	span.SetAttributes(attrPackageCount(len(pkgs)))
+	return &hedge.DebianPackages{
+		Packages: pkgs,
+	}, nil
+}
 
-			// Verify the file matches expectations:
-			if uint64(len(b)) != digest.Size {
-				return fmt.Errorf("expected %d bytes, got %d", digest.Size, len(b))
-			}
-			if actualDigest := sha256.Sum256(b); !bytes.Equal(actualDigest[:], digest.Sha256Sum) {
-				return fmt.Errorf("expected digest %x, got %x", digest.Sha256Sum, actualDigest)
-			}
+// LoadSources loads the source packages of a component. Repositories without sources return an empty list.
+func (r *RemoteRepository) LoadSources(ctx context.Context, args LoadSourcesArgs) (*hedge.DebianSources, error) {
+	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadSources", trace.WithAttributes(attrComponent(string(args.Component))))
+	defer span.End()
 
-			// Parse packages from verified file:
-			gzr, err := gzip.NewReader(bytes.NewReader(b))
-			if err != nil {
-				return err
-			}
-			pkgs, err := r.parser.Packages(ctx, gzr)
-			if err != nil {
-				return err
-			}
-			res <- pkgs
-			return nil
-		})
+	fn := fmt.Sprintf("%s/source/Sources.gz", args.Component)
+	if _, ok := args.Release.Digests[fn]; !ok {
+		return &hedge.DebianSources{}, nil
+	}
+	b, err := r.loadIndex(ctx, args.Release, fn)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
 	}
-	go func() {
-		_ = eg.Wait()
-		close(res)
-	}()
 
-	var allPackages []*hedge.DebianPackage
-	for pkgs := range res {
-		allPackages = append(allPackages, pkgs...)
+	// Parse sources from verified file:
+	gzr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
 	}
-	if err := eg.Wait(); err != nil {
-		return nil, err
+	srcs, err := r.parser.Sources(ctx, gzr)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
 	}
-	return &hedge.DebianPackages{
-		Packages: allPackages,
+	return &hedge.DebianSources{
+		Sources: srcs,
 	}, nil
 }
+
+// LoadContents loads the Contents index of a component and architecture. Repositories without one return an empty index.
+func (r *RemoteRepository) LoadContents(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianContents, error) {
+	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadContents", trace.WithAttributes(attrArchitecture(args.Architecture), attrComponent(string(args.Component))))
+	defer span.End()
+
+	in, err := r.loadCompressedIndex(ctx, args.Release, fmt.Sprintf("%s/Contents-%s", args.Component, args.Architecture))
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if in == nil {
+		return &hedge.DebianContents{}, nil
+	}
+	defer in.Close()
+	contents, err := r.parser.Contents(ctx, in)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return contents, nil
+}
+
+// LoadTranslations loads the Translation index of a component and language. Repositories without one return an empty index.
+func (r *RemoteRepository) LoadTranslations(ctx context.Context, args LoadTranslationsArgs) (*hedge.DebianTranslations, error) {
+	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadTranslations", trace.WithAttributes(attrComponent(string(args.Component)), attrLanguage(args.Language)))
+	defer span.End()
+
+	in, err := r.loadCompressedIndex(ctx, args.Release, fmt.Sprintf("%s/i18n/Translation-%s", args.Component, args.Language))
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if in == nil {
+		return &hedge.DebianTranslations{}, nil
+	}
+	defer in.Close()
+	translations, err := r.parser.Translations(ctx, in)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return translations, nil
+}
+
+// upstreamIndexCompressions are the variants of an index file that are fetched, in order of preference.
+var upstreamIndexCompressions = []Compression{CompressionGZIP, CompressionXZ, CompressionBZIP2, CompressionNone}
+
+// loadCompressedIndex fetches the first variant of an index file listed in the release, and decompresses it.
+// Returns nil if the release doesn't list the index.
+func (r *RemoteRepository) loadCompressedIndex(ctx context.Context, release *hedge.DebianRelease, fn string) (io.ReadCloser, error) {
+	for _, compression := range upstreamIndexCompressions {
+		variant := fn + compression.Extension()
+		if _, ok := release.Digests[variant]; !ok {
+			continue
+		}
+		b, err := r.loadIndex(ctx, release, variant)
+		if err != nil {
+			return nil, err
+		}
+		return compression.Reader(bytes.NewReader(b))
+	}
+	return nil, nil
+}
+
+// loadIndex fetches an index file listed in the release, like a Packages file.
+func (r *RemoteRepository) loadIndex(ctx context.Context, release *hedge.DebianRelease, fn string) ([]byte, error) {
+	// The Release file specifies the expected properties of the index file
+	// The Release file's signature was verified, so we trust it.
+	digest, ok := release.Digests[fn]
+	if !ok {
+		return nil, fmt.Errorf("release is missing %s", fn)
+	}
+	// If the URL is content-addressed, we can cache it ~forever
+	var fetchCtx context.Context
+	if strings.Contains(digest.Path, "/by-hash/") {
+		fetchCtx = cached.For(ctx, 7*24*time.Hour)
+	} else {
+		fetchCtx = ctx
+	}
+	b, _, err := fromMirrors(fetchCtx, r.tracer, r.hedgeDelay, releaseMirrors(release), func(ctx context.Context, mirror string) ([]byte, error) {
+		u, err := url.JoinPath(mirror, "dists", release.Dist, digest.Path)
+		if err != nil {
+			return nil, fmt.Errorf("building URL: %w", err)
+		}
+		b, err := r.fetchURL(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("fetching %s: %w", fn, err)
+		}
+		// Verify the file matches expectations:
+		if err := verifyFile(b, digest.Size, digest.Sha256Sum); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+	return b, err
+}
+
+func (r *RemoteRepository) LoadPoolFile(ctx context.Context, args LoadPoolFileArgs) ([]byte, error) {
+	ctx, span := r.tracer.Start(ctx, "debian.RemoteRepository.LoadPoolFile", trace.WithAttributes(attrFilename(args.Filename)))
+	defer span.End()
+
+	b, _, err := fromMirrors(ctx, r.tracer, r.hedgeDelay, releaseMirrors(args.Release), func(ctx context.Context, mirror string) ([]byte, error) {
+		u, err := url.JoinPath(mirror, args.Filename)
+		if err != nil {
+			return nil, fmt.Errorf("building URL: %w", err)
+		}
+		b, err := r.fetchURL(ctx, u)
+		if err != nil {
+			return nil, fmt.Errorf("fetching pool file: %w", err)
+		}
+		if err := verifyFile(b, args.Size, args.Sha256); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return b, nil
+}
+
+// verifyFile checks data against the size and digest from a trusted index.
+func verifyFile(b []byte, size uint64, sha []byte) error {
+	if uint64(len(b)) != size {
+		return fmt.Errorf("expected %d bytes, got %d", size, len(b))
+	}
+	if actualDigest := sha256.Sum256(b); !bytes.Equal(actualDigest[:], sha) {
+		return fmt.Errorf("expected digest %x, got %x", sha, actualDigest)
+	}
+	return nil
+}
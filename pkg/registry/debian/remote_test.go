@@ -44,7 +44,7 @@ func TestRemoteLoader(t *testing.T) {
 		})
 
 		release, err := loader(ctx, debian.LoadReleaseArgs{
-			MirrorURL:  "https://debian.mirror.rafal.ca/debian/",
+			MirrorURLs: []string{"https://debian.mirror.rafal.ca/debian/"},
 			SigningKey: string(key),
 			Dist:       "bullseye",
 		})
@@ -76,7 +76,7 @@ func TestRemoteLoader(t *testing.T) {
 		loader := cached.Wrap(storage, releases.LoadRelease, cached.AsProtoBuf[debian.LoadReleaseArgs, *hedge.DebianRelease]())
 
 		release, err := loader(ctx, debian.LoadReleaseArgs{
-			MirrorURL:  "https://debian.mirror.rafal.ca/debian/",
+			MirrorURLs: []string{"https://debian.mirror.rafal.ca/debian/"},
 			SigningKey: string(key),
 			Dist:       "bullseye",
 		})
@@ -84,6 +84,7 @@ func TestRemoteLoader(t *testing.T) {
 
 		packages, err := releases.LoadPackages(ctx, debian.LoadPackagesArgs{
 			Release:      release,
+			Component:    "main",
 			Architecture: "amd64",
 		})
 		require.NoError(t, err)
@@ -0,0 +1,432 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Index files are rendered once for each upstream release and repository configuration, then served from storage.
+// The Release file is cheap to assemble from a rendering, as every index is already digested.
+
+// renderTTL is how long renderings are stored. Renderings are keyed by everything that changes them, so this only
+// bounds how long an unused rendering takes up space.
+const renderTTL = 24 * time.Hour
+
+// configDigest identifies the configuration that filters a repository, including the policies it references.
+func configDigest(policies map[string]string, cfg *RepositoryConfig) ([]byte, error) {
+	h := sha256.New()
+	if err := json.NewEncoder(h).Encode(cfg); err != nil {
+		return nil, fmt.Errorf("encoding config: %w", err)
+	}
+	names := cfg.PolicyNames()
+	sort.Strings(names)
+	for _, name := range names {
+		policy := policies[name]
+		if _, err := fmt.Fprintf(h, "%s %d\n%s", name, len(policy), policy); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+// renderKey identifies the rendering of a release. Live repositories render the filtered packages of the release,
+// snapshots render their recorded packages.
+func (rh *repositoryHandler) renderKey(release *hedge.DebianRelease) (string, error) {
+	// Failing over to another mirror doesn't change the release:
+	release = proto.Clone(release).(*hedge.DebianRelease)
+	release.MirrorUrl = ""
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(release)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(b)
+	if rh.snapshot != nil {
+		fmt.Fprintf(h, "snapshot:%x", rh.snapshot.Digest)
+	} else {
+		fmt.Fprintf(h, "config:%x", rh.configDigest)
+	}
+	return fmt.Sprintf("%s:%x", rh.name, h.Sum(nil)), nil
+}
+
+// rendering returns the rendered index files of a release, rendering them if they are not stored.
+func (h Handler) rendering(ctx context.Context, rh *repositoryHandler, release *hedge.DebianRelease) (*hedge.DebianRendering, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.rendering", trace.WithAttributes(attrRepository(rh.name)))
+	defer span.End()
+
+	key, err := rh.renderKey(release)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if stored, err := h.renderings.Get(ctx, key); err != nil {
+		return nil, observability.CaptureError(span, err)
+	} else if stored != nil {
+		var rendering hedge.DebianRendering
+		if err := proto.Unmarshal(*stored, &rendering); err == nil {
+			span.SetAttributes(observability.CacheHit(true))
+			return &rendering, nil
+		}
+	}
+	span.SetAttributes(observability.CacheHit(false))
+
+	rendering, err := h.render(ctx, rh, release)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	b, err := proto.Marshal(rendering)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if err := h.renderings.Set(ctx, key, b, renderTTL); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return rendering, nil
+}
+
+// render renders and stores every index file of a release. Rendering a live repository also records a snapshot and
+// pins its pool files, and stores the patches from the previous rendering.
+func (h Handler) render(ctx context.Context, rh *repositoryHandler, release *hedge.DebianRelease) (*hedge.DebianRendering, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.render", trace.WithAttributes(attrRepository(rh.name)))
+	defer span.End()
+
+	rendering := &hedge.DebianRendering{Files: map[string]*hedge.DebianRelease_DigestedFile{}}
+	packages := make(map[Component]map[Architecture][]*hedge.DebianPackage, len(release.Components))
+	for _, c := range release.Components {
+		component := Component(c)
+		packages[component] = make(map[Architecture][]*hedge.DebianPackage, len(release.Architectures))
+		for _, a := range release.Architectures {
+			arch := Architecture(a)
+			pkgs, err := rh.packages(ctx, LoadPackagesArgs{
+				Release:      release,
+				Component:    component,
+				Architecture: arch,
+			})
+			if err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			packages[component][arch] = pkgs.Packages
+
+			var buf bytes.Buffer
+			if err := writePackagesFile(&buf, pkgs.Packages); err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			if err := h.storeIndex(ctx, rendering, fmt.Sprintf("%s/binary-%s/Packages", component, arch), buf.Bytes()); err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			// Patches from previous renderings let clients download small deltas:
+			if rh.snapshot == nil {
+				if _, err := h.pdiffs.Update(ctx, rh.name, component, arch, buf.Bytes()); err != nil {
+					return nil, observability.CaptureError(span, fmt.Errorf("updating packages diffs: %w", err))
+				}
+			}
+		}
+	}
+
+	if rh.sources != nil {
+		for _, c := range release.Components {
+			srcs, err := rh.sources(ctx, LoadSourcesArgs{Release: release, Component: Component(c)})
+			if err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			var buf bytes.Buffer
+			if err := writeSourcesFile(&buf, srcs.Sources); err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			if err := h.storeIndex(ctx, rendering, fmt.Sprintf("%s/source/Sources", c), buf.Bytes()); err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+		}
+	}
+	if rh.contentsIndices != nil {
+		for _, c := range release.Components {
+			for _, a := range release.Architectures {
+				contents, err := rh.contentsIndices(ctx, LoadPackagesArgs{Release: release, Component: Component(c), Architecture: Architecture(a)})
+				if err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+				var buf bytes.Buffer
+				if err := WriteContentsFile(&buf, contents); err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+				if err := h.storeIndex(ctx, rendering, fmt.Sprintf("%s/Contents-%s", c, a), buf.Bytes()); err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+			}
+		}
+	}
+	if rh.translations != nil {
+		for _, c := range release.Components {
+			for _, language := range rh.languages {
+				translations, err := rh.translations(ctx, LoadTranslationsArgs{Release: release, Component: Component(c), Language: language})
+				if err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+				var buf bytes.Buffer
+				if err := WriteTranslationFile(&buf, translations); err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+				if err := h.storeIndex(ctx, rendering, fmt.Sprintf("%s/i18n/Translation-%s", c, language), buf.Bytes()); err != nil {
+					return nil, observability.CaptureError(span, err)
+				}
+			}
+		}
+	}
+
+	if rh.snapshot == nil {
+		if err := h.snapshots.Record(ctx, rh.name, release, packages); err != nil {
+			return nil, observability.CaptureError(span, fmt.Errorf("recording snapshot: %w", err))
+		}
+		h.pinSnapshot(rh, release, packages)
+	}
+	span.SetAttributes(attrFileCount(len(rendering.Files)))
+	return rendering, nil
+}
+
+// storeIndex stores each variant of an index file that is listed in the Release file, and adds it to a rendering.
+func (h Handler) storeIndex(ctx context.Context, rendering *hedge.DebianRendering, fn string, content []byte) error {
+	for _, compression := range IndexedCompressions {
+		var buf bytes.Buffer
+		if err := compression.Compress(&buf, bytes.NewReader(content)); err != nil {
+			return err
+		}
+		sha := sha256.Sum256(buf.Bytes())
+		md := md5.Sum(buf.Bytes())
+		if err := h.rendered.Set(ctx, hex.EncodeToString(sha[:]), buf.Bytes(), renderTTL); err != nil {
+			return err
+		}
+		variant := fn + compression.Extension()
+		rendering.Files[variant] = &hedge.DebianRelease_DigestedFile{
+			Path:      variant,
+			Size:      uint64(buf.Len()),
+			Md5Sum:    md[:],
+			Sha256Sum: sha[:],
+		}
+	}
+	return nil
+}
+
+// renderedFile loads a rendered file, verifying it against the rendering.
+func (h Handler) renderedFile(ctx context.Context, f *hedge.DebianRelease_DigestedFile) ([]byte, error) {
+	b, err := h.rendered.Get(ctx, hex.EncodeToString(f.Sha256Sum))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("rendered file %s not found", f.Path)
+	}
+	if err := verifyFile(*b, f.Size, f.Sha256Sum); err != nil {
+		return nil, fmt.Errorf("rendered file %s: %w", f.Path, err)
+	}
+	return *b, nil
+}
+
+// currentRendering returns the rendering of a request's repository, or nil if the repository is not found.
+func (h Handler) currentRendering(ctx context.Context, req base.HttpRequest) (*hedge.DebianRendering, error) {
+	rh, err := h.repository(ctx, req)
+	if err != nil || rh == nil {
+		return nil, err
+	}
+	release, err := h.loadRelease(ctx, rh)
+	if err != nil {
+		return nil, err
+	}
+	return h.rendering(ctx, rh, release)
+}
+
+// serveRendered serves a rendered index file. Compressions that aren't listed in the Release file are compressed
+// from the uncompressed file.
+func (h Handler) serveRendered(ctx context.Context, req base.HttpRequest, fn string, compression Compression) (*hedge.HttpResponse, error) {
+	rendering, err := h.currentRendering(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if rendering == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	if f, ok := rendering.Files[fn+compression.Extension()]; ok {
+		b, err := h.renderedFile(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		return &hedge.HttpResponse{
+			Body: b,
+		}, nil
+	}
+
+	f, ok := rendering.Files[fn]
+	if !ok {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	b, err := h.renderedFile(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	var compressed bytes.Buffer
+	if err := compression.Compress(&compressed, bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+	return &hedge.HttpResponse{
+		Body: compressed.Bytes(),
+	}, nil
+}
+
+// serveRenderedByHash serves the rendered index file in a directory that matches the request's digest.
+func (h Handler) serveRenderedByHash(ctx context.Context, req base.HttpRequest, dir string) (*hedge.HttpResponse, error) {
+	rendering, err := h.currentRendering(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := hex.DecodeString(req.PathVars["digest"])
+	if err != nil || rendering == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	for _, f := range rendering.Files {
+		if path.Dir(f.Path) != dir || !bytes.Equal(f.Sha256Sum, digest) {
+			continue
+		}
+		b, err := h.renderedFile(ctx, f)
+		if err != nil {
+			return nil, err
+		}
+		return &hedge.HttpResponse{
+			Body: b,
+		}, nil
+	}
+	return &hedge.HttpResponse{
+		StatusCode: http.StatusNotFound,
+	}, nil
+}
+
+// releaseFile returns the unsigned Release file. InRelease and Release.gpg both sign these bytes.
+// Clients fetch Release and Release.gpg separately, so the Release file is stored with its Valid-Until and served
+// from storage until half of validFor has passed, rather than rendered again for every request.
+func (h Handler) releaseFile(ctx context.Context, rh *repositoryHandler) ([]byte, error) {
+	ctx, span := h.tracer.Start(ctx, "debian.releaseFile", trace.WithAttributes(attrRepository(rh.name)))
+	defer span.End()
+
+	release, err := h.loadRelease(ctx, rh)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	renderKey, err := rh.renderKey(release)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	key := fmt.Sprintf("Release:%s", renderKey)
+	if stored, err := h.renderings.Get(ctx, key); err != nil {
+		return nil, observability.CaptureError(span, err)
+	} else if stored != nil {
+		span.SetAttributes(observability.CacheHit(true))
+		return *stored, nil
+	}
+	span.SetAttributes(observability.CacheHit(false))
+
+	b, err := h.renderReleaseFile(ctx, rh, release)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	if err := h.renderings.Set(ctx, key, b, rh.validFor/2); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return b, nil
+}
+
+// renderReleaseFile renders a Release file that is valid for validFor.
+func (h Handler) renderReleaseFile(ctx context.Context, rh *repositoryHandler, release *hedge.DebianRelease) ([]byte, error) {
+	// The Release file contains hashes of all index files, which are rendered once per release:
+	rendering, err := h.rendering(ctx, rh, release)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]PackagesDigest, 0, len(rendering.Files))
+	for _, f := range rendering.Files {
+		indexes = append(indexes, PackagesDigest{
+			Path:   f.Path,
+			Size:   int(f.Size),
+			Sha256: f.Sha256Sum,
+			Md5:    f.Md5Sum,
+		})
+	}
+	if rh.snapshot == nil {
+		// Patches from previous renderings let clients download small deltas:
+		for _, c := range release.Components {
+			for _, a := range release.Architectures {
+				index, err := h.diffIndex(ctx, rh, rendering, Component(c), Architecture(a))
+				if err != nil {
+					return nil, err
+				}
+				if index != nil {
+					indexes = append(indexes, *index)
+				}
+			}
+		}
+	}
+
+	// Replace the upstream's Valid-Until with our own, so clients notice if hedge stops refreshing:
+	release = proto.Clone(release).(*hedge.DebianRelease)
+	release.ValidUntil = timestamppb.New(h.now().UTC().Truncate(time.Second).Add(rh.validFor))
+
+	var buf bytes.Buffer
+	if err := WriteReleaseFile(ctx, release, nil, &buf, indexes...); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// signed returns a signature of a Release file, which is stored until the Release file changes.
+func (h Handler) signed(ctx context.Context, rh *repositoryHandler, kind string, release []byte, sign func([]byte, time.Time) ([]byte, error)) ([]byte, error) {
+	ctx, span := h.tracer.Start(ctx, fmt.Sprintf("debian.%s", kind))
+	defer span.End()
+
+	now := h.now()
+	signers, err := rh.signers(now)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	// Keys are rotated without changing the Release file, so they identify the signature too:
+	digest := sha256.New()
+	digest.Write(release)
+	for _, signer := range signers {
+		digest.Write(signer.PrimaryKey.Fingerprint)
+	}
+	key := fmt.Sprintf("%s:%x", kind, digest.Sum(nil))
+	if stored, err := h.renderings.Get(ctx, key); err != nil {
+		return nil, observability.CaptureError(span, err)
+	} else if stored != nil {
+		span.SetAttributes(observability.CacheHit(true))
+		return *stored, nil
+	}
+	span.SetAttributes(observability.CacheHit(false))
+
+	b, err := sign(release, now)
+	if err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	// The Release file is replaced after half of validFor, so the signature is not needed for longer:
+	if err := h.renderings.Set(ctx, key, b, rh.validFor/2); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	return b, nil
+}
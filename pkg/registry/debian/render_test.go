@@ -0,0 +1,90 @@
+package debian_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+)
+
+// renderCountingStorage counts the renderings stored for the "test" repository.
+type renderCountingStorage struct {
+	cached.ByteStorage
+	mu         sync.Mutex
+	renderings int
+}
+
+func (s *renderCountingStorage) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if strings.HasPrefix(key, "debian_renderings:test:") {
+		s.mu.Lock()
+		s.renderings++
+		s.mu.Unlock()
+	}
+	return s.ByteStorage.Set(ctx, key, value, ttl)
+}
+
+func (s *renderCountingStorage) Renderings() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.renderings
+}
+
+func TestHandler_Rendering(t *testing.T) {
+	storage := &renderCountingStorage{ByteStorage: cached.InMemory[string, []byte]()}
+	mirror := newTestMirror(t)
+	testpkg := map[string]string{"policy.cue": `name: "testpkg"`}
+	h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(mirror.URL, mirror.PubKey, "policy.cue"), testpkg)
+
+	inRelease := get(t, h, "/debian/dists/test/InRelease")
+	require.Equal(t, http.StatusOK, inRelease.Code)
+	release := getRelease(t, h, "/debian/dists/test/InRelease")
+	packages := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+	require.Equal(t, http.StatusOK, packages.Code)
+	digest := sha256.Sum256(packages.Body.Bytes())
+	assert.Equal(t, release.Digests["main/binary-amd64/Packages"].Sha256Sum, digest[:])
+	assert.Equal(t, 1, storage.Renderings())
+
+	t.Run("reused", func(t *testing.T) {
+		// Another instance sharing the storage serves the same files without rendering them:
+		h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(mirror.URL, mirror.PubKey, "policy.cue"), testpkg)
+		res := get(t, h, "/debian/dists/test/InRelease")
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, inRelease.Body.Bytes(), res.Body.Bytes())
+		res = get(t, h, "/debian/dists/test/main/binary-amd64/Packages.gz")
+		require.Equal(t, http.StatusOK, res.Code)
+		digest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, release.Digests["main/binary-amd64/Packages.gz"].Sha256Sum, digest[:])
+		assert.Equal(t, 1, storage.Renderings())
+	})
+
+	t.Run("policy changed", func(t *testing.T) {
+		everything := map[string]string{"policy.cue": `name: string`}
+		h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(mirror.URL, mirror.PubKey, "policy.cue"), everything)
+		changed := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.NotEqual(t, release.Digests["contrib/binary-amd64/Packages"].Sha256Sum, changed.Digests["contrib/binary-amd64/Packages"].Sha256Sum)
+		assert.Equal(t, 2, storage.Renderings())
+
+		// Reverting the policy serves the first rendering, without patches that lead to the second:
+		h = newTestHandlerWithStorage(t, storage, testRepositoryConfig(mirror.URL, mirror.PubKey, "policy.cue"), testpkg)
+		reverted := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.Equal(t, release.Digests["contrib/binary-amd64/Packages"].Sha256Sum, reverted.Digests["contrib/binary-amd64/Packages"].Sha256Sum)
+		assert.NotContains(t, reverted.Digests, "contrib/binary-amd64/Packages.diff/Index")
+		assert.Equal(t, 2, storage.Renderings())
+	})
+
+	t.Run("upstream changed", func(t *testing.T) {
+		after := newTestMirror(t)
+		after.SetDates(t, time.Date(2022, time.July, 10, 9, 43, 23, 0, time.UTC), time.Time{})
+		h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(after.URL, after.PubKey, "policy.cue"), testpkg)
+		res := get(t, h, "/debian/dists/test/InRelease")
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, 3, storage.Renderings())
+	})
+}
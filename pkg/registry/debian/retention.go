@@ -0,0 +1,209 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// retainedTTL is how long retained versions and their pool files are stored. Pool files are pinned again after half
+// of this, so they don't expire while they are retained.
+const retainedTTL = 365 * 24 * time.Hour
+
+// retentionStore records the package versions each index has allowed, and when their pool files are pinned until.
+type retentionStore struct {
+	tracer  trace.Tracer
+	storage cached.ByteStorage
+	pins    *pinnedFiles
+	now     func() time.Time
+
+	// locks serialize updates to the retained versions of each index within this process.
+	locks sync.Map
+}
+
+func newRetentionStore(tracer trace.Tracer, storage cached.ByteStorage, pins *pinnedFiles) *retentionStore {
+	return &retentionStore{
+		tracer:  tracer,
+		storage: cached.WithPrefix[string, []byte]("debian_retained", storage),
+		pins:    pins,
+		now:     time.Now,
+	}
+}
+
+// retaining adds the versions retained by cfg to the packages of each index. pin is called with the versions whose
+// pool files must be pinned, and must not block.
+func (s *retentionStore) retaining(repo string, cfg RetentionConfig, policy func(Component, string) filter.Predicate[*hedge.DebianPackage], pin func(LoadPackagesArgs, []*hedge.DebianPackage), wrapped cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		pkgs, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		retained, unpinned, err := s.Retain(ctx, repo, cfg, policy, args, pkgs.Packages)
+		if err != nil {
+			return nil, err
+		}
+		pin(args, unpinned)
+		if len(retained) == 0 {
+			return pkgs, nil
+		}
+		merged := make([]*hedge.DebianPackage, 0, len(pkgs.Packages)+len(retained))
+		merged = append(merged, pkgs.Packages...)
+		merged = append(merged, retained...)
+		return &hedge.DebianPackages{Packages: merged}, nil
+	}
+}
+
+// Retain records the allowed packages of an index, and returns the versions upstream removed that are still
+// retained. Versions are retained from when they are first allowed, so Retain also returns the versions whose pool
+// files must be pinned before upstream removes them. Versions are only returned as retained once their pool file is
+// pinned.
+func (s *retentionStore) Retain(ctx context.Context, repo string, cfg RetentionConfig, policy func(Component, string) filter.Predicate[*hedge.DebianPackage], args LoadPackagesArgs, allowed []*hedge.DebianPackage) (retained, unpinned []*hedge.DebianPackage, err error) {
+	ctx, span := s.tracer.Start(ctx, "debian.retainPackages", trace.WithAttributes(attrRepository(repo), attrComponent(string(args.Component)), attrArchitecture(args.Architecture)))
+	defer span.End()
+
+	key := fmt.Sprintf("%s:%s", repo, snapshotPackagesKey(args.Component, args.Architecture))
+	defer s.lock(key)()
+	stored, err := s.load(ctx, key)
+	if err != nil {
+		return nil, nil, observability.CaptureError(span, err)
+	}
+	// Entries are updated in place, keep what was stored to skip unchanged writes:
+	previous := proto.Clone(stored).(*hedge.DebianRetainedPackages)
+	now := s.now()
+	entries := make(map[string]*hedge.DebianRetainedPackages_Entry, len(stored.Entries)+len(allowed))
+	for _, e := range stored.Entries {
+		entries[retainedKey(e.Package)] = e
+	}
+	current := make(map[string]struct{}, len(allowed))
+	for _, pkg := range allowed {
+		k := retainedKey(pkg)
+		current[k] = struct{}{}
+		e, ok := entries[k]
+		if !ok {
+			e = &hedge.DebianRetainedPackages_Entry{}
+			entries[k] = e
+		}
+		if !proto.Equal(e.Package, pkg) {
+			e.Package = pkg
+		}
+		e.Removed = nil
+	}
+
+	// Rank the versions of each package, newest first:
+	byPackage := map[string][]*hedge.DebianRetainedPackages_Entry{}
+	for _, e := range entries {
+		name := e.Package.Name + ":" + e.Package.Architecture
+		byPackage[name] = append(byPackage[name], e)
+	}
+	kept := &hedge.DebianRetainedPackages{Entries: make([]*hedge.DebianRetainedPackages_Entry, 0, len(entries))}
+	for _, versions := range byPackage {
+		sort.Slice(versions, func(i, j int) bool {
+			return compareRetainedVersions(versions[i].Package.Version, versions[j].Package.Version) > 0
+		})
+		for rank, e := range versions {
+			_, isCurrent := current[retainedKey(e.Package)]
+			if !isCurrent {
+				if e.Removed == nil {
+					e.Removed = timestamppb.New(now)
+				}
+				byCount := cfg.Versions > 0 && rank < cfg.Versions
+				byPeriod := cfg.Period > 0 && now.Sub(e.Removed.AsTime()) < cfg.Period
+				if !byCount && !byPeriod {
+					continue
+				}
+				if ok, err := policy(args.Component, e.Package.Filename)(ctx, e.Package); err != nil {
+					return nil, nil, observability.CaptureError(span, err)
+				} else if !ok {
+					continue
+				}
+			}
+
+			// Pins are looked up while they are missing, or due to be extended after half of retainedTTL:
+			if e.PinnedUntil == nil || e.PinnedUntil.AsTime().Before(now.Add(retainedTTL/2)) {
+				until, err := s.pins.Until(ctx, e.Package.Sha256)
+				if err != nil {
+					return nil, nil, observability.CaptureError(span, err)
+				}
+				if until.After(now) && (e.PinnedUntil == nil || !until.Equal(e.PinnedUntil.AsTime())) {
+					e.PinnedUntil = timestamppb.New(until)
+				}
+				if until.Before(now.Add(retainedTTL / 2)) {
+					unpinned = append(unpinned, e.Package)
+				}
+			}
+			kept.Entries = append(kept.Entries, e)
+			if !isCurrent && e.PinnedUntil != nil && e.PinnedUntil.AsTime().After(now) {
+				retained = append(retained, e.Package)
+			}
+		}
+	}
+
+	// Sort for stable storage and indices:
+	sort.Slice(kept.Entries, func(i, j int) bool {
+		return retainedKey(kept.Entries[i].Package) < retainedKey(kept.Entries[j].Package)
+	})
+	sort.Slice(retained, func(i, j int) bool {
+		return retainedKey(retained[i]) < retainedKey(retained[j])
+	})
+	if !proto.Equal(previous, kept) {
+		if err := s.store(ctx, key, kept); err != nil {
+			return nil, nil, observability.CaptureError(span, err)
+		}
+	}
+	span.SetAttributes(attrPackageCount(len(retained)))
+	return retained, unpinned, nil
+}
+
+// lock locks the retained versions of an index, and returns the function that unlocks them.
+func (s *retentionStore) lock(key string) func() {
+	mu, _ := s.locks.LoadOrStore(key, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (s *retentionStore) load(ctx context.Context, key string) (*hedge.DebianRetainedPackages, error) {
+	var retained hedge.DebianRetainedPackages
+	b, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return &retained, nil
+	}
+	if err := proto.Unmarshal(*b, &retained); err != nil {
+		return nil, fmt.Errorf("decoding retained packages: %w", err)
+	}
+	return &retained, nil
+}
+
+func (s *retentionStore) store(ctx context.Context, key string, retained *hedge.DebianRetainedPackages) error {
+	b, err := proto.Marshal(retained)
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(ctx, key, b, retainedTTL)
+}
+
+// retainedKey identifies a version of a package in an index.
+func retainedKey(pkg *hedge.DebianPackage) string {
+	return strings.Join([]string{pkg.Name, pkg.Architecture, pkg.Version}, " ")
+}
+
+// compareRetainedVersions orders Debian versions, falling back to strings for versions that don't parse.
+func compareRetainedVersions(a, b string) int {
+	if c, err := CompareVersions(a, b); err == nil {
+		return c
+	}
+	return strings.Compare(a, b)
+}
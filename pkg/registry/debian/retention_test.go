@@ -0,0 +1,116 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+func TestHandler_Retention(t *testing.T) {
+	deb, err := os.ReadFile("testdata/testpkg_1.2.3_amd64.deb")
+	require.NoError(t, err)
+	newDebPath := strings.Replace(testDebPath, "1.2.3", "1.2.4", 1)
+	policies := map[string]string{
+		"testpkg.cue": `name: "testpkg"`,
+		"latest.cue":  `name: "testpkg", version: "1.2.4"`,
+	}
+	versions := func(t *testing.T, h http.Handler) []string {
+		t.Helper()
+		res := get(t, h, "/debian/dists/test/main/binary-amd64/Packages")
+		require.Equal(t, http.StatusOK, res.Code)
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		var versions []string
+		for _, pkg := range pkgs {
+			versions = append(versions, pkg.Version)
+		}
+		sort.Strings(versions)
+		return versions
+	}
+
+	for label, tc := range map[string]struct {
+		retention *debian.RetentionConfig
+		policy    string
+		notPinned bool
+		expected  []string
+	}{
+		"no retention":    {policy: "testpkg.cue", expected: []string{"1.2.4"}},
+		"versions":        {retention: &debian.RetentionConfig{Versions: 2}, policy: "testpkg.cue", expected: []string{"1.2.3", "1.2.4"}},
+		"fewer versions":  {retention: &debian.RetentionConfig{Versions: 1}, policy: "testpkg.cue", expected: []string{"1.2.4"}},
+		"period":          {retention: &debian.RetentionConfig{Period: time.Hour}, policy: "testpkg.cue", expected: []string{"1.2.3", "1.2.4"}},
+		"refused version": {retention: &debian.RetentionConfig{Versions: 2}, policy: "latest.cue", expected: []string{"1.2.4"}},
+		"not pinned":      {retention: &debian.RetentionConfig{Versions: 2}, policy: "testpkg.cue", notPinned: true, expected: []string{"1.2.4"}},
+	} {
+		t.Run(label, func(t *testing.T) {
+			storage := cached.InMemory[string, []byte]()
+			before := newTestMirror(t)
+			// Pool files are pinned in the background, versions whose file upstream removed first aren't retained:
+			if tc.notPinned {
+				before.SetFile("/"+testDebPath, nil)
+			}
+			repoCfg := testRepositoryConfig(before.URL, before.PubKey, "testpkg.cue")
+			repoCfg.Retention = tc.retention
+			h := newTestHandlerWithStorage(t, storage, repoCfg, policies)
+			assert.Equal(t, []string{"1.2.3"}, versions(t, h))
+			if tc.retention != nil && !tc.notPinned {
+				waitPinned(t, storage, deb)
+			}
+
+			// Upstream replaces 1.2.3 with 1.2.4, and deletes the old pool file:
+			after := newTestMirror(t)
+			packages := bytes.ReplaceAll(after.packages["main"], []byte("1.2.3"), []byte("1.2.4"))
+			after.SetPackages(t, "main", packages)
+			after.SetFile("/"+newDebPath, deb)
+			after.SetFile("/"+testDebPath, nil)
+			repoCfg = testRepositoryConfig(after.URL, after.PubKey, tc.policy)
+			repoCfg.Retention = tc.retention
+			h = newTestHandlerWithStorage(t, storage, repoCfg, policies)
+			assert.Equal(t, tc.expected, versions(t, h))
+
+			res := get(t, h, "/debian/dists/test/"+testDebPath)
+			if len(tc.expected) == 1 {
+				assert.NotEqual(t, http.StatusOK, res.Code)
+				return
+			}
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, deb, res.Body.Bytes())
+		})
+	}
+}
+
+// waitPinned waits for a pool file to be pinned in the background.
+func waitPinned(t *testing.T, storage cached.ByteStorage, b []byte) {
+	t.Helper()
+	key := fmt.Sprintf("debian_pinned:%x", sha256.Sum256(b))
+	require.Eventually(t, func() bool {
+		pinned, err := storage.Get(context.Background(), key)
+		require.NoError(t, err)
+		return pinned != nil
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestNewHandler_InvalidRetention(t *testing.T) {
+	repoCfg := testRepositoryConfig("http://localhost", "", "testpkg.cue")
+	repoCfg.Retention = &debian.RetentionConfig{}
+	storage := cached.InMemory[string, []byte]()
+	_, err := debian.NewHandler(base.NewCachedMux(observability.NoopTracer, storage), observability.NoopTracer, storage, &http.Client{}, registry.EcosystemConfig{
+		Repositories: map[string]registry.RepositoryConfig{"test": repoCfg},
+		Policies:     map[string]string{"testpkg.cue": `name: "testpkg"`},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package debian
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+)
+
+// releaseDateTTL is how long the newest Date of an upstream is remembered.
+const releaseDateTTL = 365 * 24 * time.Hour
+
+// releaseDates remembers the newest Date seen for each upstream, so a mirror can't roll back to an older release
+// that is still validly signed.
+type releaseDates struct {
+	storage cached.ByteStorage
+
+	// mu serializes updates within this process.
+	mu sync.Mutex
+}
+
+func newReleaseDates(storage cached.ByteStorage) *releaseDates {
+	return &releaseDates{
+		storage: cached.WithPrefix[string, []byte]("debian_release_dates", storage),
+	}
+}
+
+// Observe records the Date of a release from an upstream. Returns an error if the upstream has served a newer release.
+func (d *releaseDates) Observe(ctx context.Context, upstream string, date time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, err := d.storage.Get(ctx, upstream)
+	if err != nil {
+		return err
+	}
+	if b != nil {
+		var newest time.Time
+		if err := newest.UnmarshalText(*b); err != nil {
+			return fmt.Errorf("decoding release date: %w", err)
+		}
+		if date.Before(newest) {
+			return fmt.Errorf("release dated %s is older than %s", date.Format(time.RFC1123), newest.Format(time.RFC1123))
+		}
+		if date.Equal(newest) {
+			return nil
+		}
+	}
+
+	encoded, err := date.MarshalText()
+	if err != nil {
+		return err
+	}
+	return d.storage.Set(ctx, upstream, encoded, releaseDateTTL)
+}
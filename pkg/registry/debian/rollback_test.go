@@ -0,0 +1,82 @@
+package debian_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+)
+
+func TestHandler_ValidUntil(t *testing.T) {
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+
+	t.Run("signed by hedge", func(t *testing.T) {
+		mirror := newTestMirror(t)
+		// The upstream's Valid-Until is replaced by hedge's:
+		mirror.SetDates(t, time.Now().Add(-time.Hour), time.Now().Add(365*24*time.Hour))
+		repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.ValidFor = 48 * time.Hour
+		release := getRelease(t, newTestHandler(t, repoCfg, policies), "/debian/dists/test/InRelease")
+		expected := time.Now().Add(48 * time.Hour)
+		assert.WithinDuration(t, expected, release.ValidUntil.AsTime(), time.Hour)
+		assert.False(t, release.ValidUntil.AsTime().After(expected))
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mirror := newTestMirror(t)
+		release := getRelease(t, newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies), "/debian/dists/test/InRelease")
+		assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), release.ValidUntil.AsTime(), time.Hour)
+	})
+
+	t.Run("stored", func(t *testing.T) {
+		// Release and Release.gpg are fetched separately, so Valid-Until doesn't move between requests:
+		mirror := newTestMirror(t)
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		first := get(t, h, "/debian/dists/test/Release")
+		require.Equal(t, http.StatusOK, first.Code)
+		time.Sleep(1100 * time.Millisecond)
+		second := get(t, h, "/debian/dists/test/Release")
+		require.Equal(t, http.StatusOK, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+	})
+
+	t.Run("expired upstream", func(t *testing.T) {
+		mirror := newTestMirror(t)
+		mirror.SetDates(t, time.Now().Add(-48*time.Hour), time.Now().Add(-24*time.Hour))
+		res := get(t, newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies), "/debian/dists/test/InRelease")
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+	})
+}
+
+func TestHandler_Rollback(t *testing.T) {
+	policies := map[string]string{"testpkg.cue": `name: "testpkg"`}
+	storage := cached.InMemory[string, []byte]()
+
+	newer := newTestMirror(t)
+	newerDate := time.Date(2022, time.July, 10, 9, 43, 23, 0, time.UTC)
+	newer.SetDates(t, newerDate, time.Time{})
+	release := getRelease(t, newTestHandlerWithStorage(t, storage, testRepositoryConfig(newer.URL, newer.PubKey, "testpkg.cue"), policies), "/debian/dists/test/InRelease")
+	assert.Equal(t, newerDate, release.Date.AsTime())
+
+	// The older release is still validly signed, but is refused once a newer release has been seen:
+	older := newTestMirror(t)
+	h := newTestHandlerWithStorage(t, storage, testRepositoryConfig(older.URL, older.PubKey, "testpkg.cue"), policies)
+	res := get(t, h, "/debian/dists/test/InRelease")
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	t.Run("other mirror", func(t *testing.T) {
+		repoCfg := testRepositoryConfig(older.URL, older.PubKey, "testpkg.cue")
+		repoCfg.Source.Upstream.Mirrors = []string{newer.URL}
+		release := getRelease(t, newTestHandlerWithStorage(t, storage, repoCfg, policies), "/debian/dists/test/InRelease")
+		assert.Equal(t, newerDate, release.Date.AsTime())
+	})
+
+	t.Run("other upstream", func(t *testing.T) {
+		// Upstreams are tracked separately:
+		release := getRelease(t, newTestHandler(t, testRepositoryConfig(older.URL, older.PubKey, "testpkg.cue"), policies), "/debian/dists/test/InRelease")
+		assert.True(t, release.Date.AsTime().Before(newerDate))
+	})
+}
@@ -0,0 +1,313 @@
+package debian
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SnapshotIDFormat is the layout of snapshot IDs, which are timestamps like snapshot.debian.org.
+const SnapshotIDFormat = "20060102T150405Z"
+
+// snapshotTTL is how long snapshots are stored. Snapshots are immutable, so this can be long.
+const snapshotTTL = 10 * 365 * 24 * time.Hour
+
+const snapshotIndexKey = "index"
+
+// snapshotStore persists a snapshot of a repository whenever its filtered packages change. Snapshots are recorded
+// when the live repository is rendered, so a change is recorded once clients request the repository after it.
+// Snapshots contain the Release and Packages indices, Sources, Contents and Translation indices are not kept. Their
+// pool files are pinned for as long as snapshots are stored, so they are served after upstream removes them.
+type snapshotStore struct {
+	tracer  trace.Tracer
+	storage cached.ByteStorage
+	now     func() time.Time
+
+	// mu serializes updates to the index within this process.
+	mu sync.Mutex
+}
+
+func newSnapshotStore(tracer trace.Tracer, storage cached.ByteStorage) *snapshotStore {
+	return &snapshotStore{
+		tracer:  tracer,
+		storage: cached.WithPrefix[string, []byte]("debian_snapshots", storage),
+		now:     time.Now,
+	}
+}
+
+// Record stores a snapshot of the repository, unless the packages match the latest snapshot.
+func (s *snapshotStore) Record(ctx context.Context, repo string, release *hedge.DebianRelease, packages map[Component]map[Architecture][]*hedge.DebianPackage) error {
+	ctx, span := s.tracer.Start(ctx, "debian.recordSnapshot", trace.WithAttributes(attrRepository(repo)))
+	defer span.End()
+
+	snapshot := &hedge.DebianSnapshot{
+		Release:  release,
+		Packages: make(map[string]*hedge.DebianPackages, len(packages)*len(release.Architectures)),
+	}
+	for component, archs := range packages {
+		for arch, pkgs := range archs {
+			snapshot.Packages[snapshotPackagesKey(component, arch)] = &hedge.DebianPackages{Packages: pkgs}
+		}
+	}
+	digest, err := snapshotDigest(snapshot)
+	if err != nil {
+		return observability.CaptureError(span, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, err := s.index(ctx, repo)
+	if err != nil {
+		return observability.CaptureError(span, err)
+	}
+	var latest *hedge.DebianSnapshots_Entry
+	if n := len(index.Snapshots); n > 0 {
+		latest = index.Snapshots[n-1]
+		if bytes.Equal(latest.Digest, digest) {
+			return nil
+		}
+	}
+
+	// IDs must be unique and ordered, even if the clock has not advanced past the latest snapshot:
+	created := s.now().UTC().Truncate(time.Second)
+	if latest != nil {
+		if latestCreated, err := time.Parse(SnapshotIDFormat, latest.Id); err == nil && !created.After(latestCreated) {
+			created = latestCreated.Add(time.Second)
+		}
+	}
+	snapshot.Id = created.Format(SnapshotIDFormat)
+	snapshot.Created = timestamppb.New(created)
+	snapshot.Digest = digest
+	span.SetAttributes(attrSnapshot(snapshot.Id))
+
+	b, err := proto.Marshal(snapshot)
+	if err != nil {
+		return observability.CaptureError(span, err)
+	}
+	if err := s.storage.Set(ctx, snapshotKey(repo, snapshot.Id), b, snapshotTTL); err != nil {
+		return observability.CaptureError(span, err)
+	}
+	index.Snapshots = append(index.Snapshots, &hedge.DebianSnapshots_Entry{Id: snapshot.Id, Digest: digest})
+	if err := s.setIndex(ctx, repo, index); err != nil {
+		return observability.CaptureError(span, err)
+	}
+	return nil
+}
+
+// Lookup returns the latest snapshot taken at or before a timestamp, so clients can pin to any point in time.
+// Returns nil if there is no such snapshot.
+func (s *snapshotStore) Lookup(ctx context.Context, repo, timestamp string) (*hedge.DebianSnapshot, error) {
+	if _, err := time.Parse(SnapshotIDFormat, timestamp); err != nil {
+		return nil, nil
+	}
+	index, err := s.index(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	var id string
+	for _, entry := range index.Snapshots {
+		// IDs are fixed-width timestamps, so they sort as strings:
+		if entry.Id > timestamp {
+			break
+		}
+		id = entry.Id
+	}
+	if id == "" {
+		return nil, nil
+	}
+
+	b, err := s.storage.Get(ctx, snapshotKey(repo, id))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, fmt.Errorf("snapshot %s of %s is missing", id, repo)
+	}
+	var snapshot hedge.DebianSnapshot
+	if err := proto.Unmarshal(*b, &snapshot); err != nil {
+		return nil, fmt.Errorf("decoding snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// IDs returns the IDs of a repository's snapshots, oldest first.
+func (s *snapshotStore) IDs(ctx context.Context, repo string) ([]string, error) {
+	index, err := s.index(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(index.Snapshots))
+	for _, entry := range index.Snapshots {
+		ids = append(ids, entry.Id)
+	}
+	return ids, nil
+}
+
+func (s *snapshotStore) index(ctx context.Context, repo string) (*hedge.DebianSnapshots, error) {
+	var index hedge.DebianSnapshots
+	b, err := s.storage.Get(ctx, snapshotKey(repo, snapshotIndexKey))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return &index, nil
+	}
+	if err := proto.Unmarshal(*b, &index); err != nil {
+		return nil, fmt.Errorf("decoding snapshot index: %w", err)
+	}
+	return &index, nil
+}
+
+func (s *snapshotStore) setIndex(ctx context.Context, repo string, index *hedge.DebianSnapshots) error {
+	b, err := proto.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(ctx, snapshotKey(repo, snapshotIndexKey), b, snapshotTTL)
+}
+
+func snapshotKey(repo, id string) string {
+	return fmt.Sprintf("%s:%s", repo, id)
+}
+
+func snapshotPackagesKey(component Component, arch Architecture) string {
+	return fmt.Sprintf("%s/binary-%s", component, arch)
+}
+
+// pinSnapshot pins the pool files of a live repository's packages for as long as snapshots are stored. Files that
+// fail are tried again when the repository is rendered next.
+func (h Handler) pinSnapshot(rh *repositoryHandler, release *hedge.DebianRelease, packages map[Component]map[Architecture][]*hedge.DebianPackage) {
+	prefix := path.Join("dists", rh.name) + "/"
+	for component, archs := range packages {
+		for arch, pkgs := range archs {
+			upstream := make([]*hedge.DebianPackage, 0, len(pkgs))
+			for _, pkg := range pkgs {
+				pkg := proto.Clone(pkg).(*hedge.DebianPackage)
+				pkg.Filename = strings.TrimPrefix(pkg.Filename, prefix)
+				upstream = append(upstream, pkg)
+			}
+			h.pinPoolFiles(rh, LoadPackagesArgs{Release: release, Component: component, Architecture: arch}, upstream, snapshotTTL)
+		}
+	}
+}
+
+// snapshotDigest identifies the packages of a snapshot. The release is excluded, as sources like GitHub
+// change the Date without changing packages.
+func snapshotDigest(snapshot *hedge.DebianSnapshot) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(&hedge.DebianSnapshot{Packages: snapshot.Packages})
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(b)
+	return digest[:], nil
+}
+
+// atSnapshot returns a read-only view of the repository as it was in the snapshot. It serves binary packages only.
+func (rh *repositoryHandler) atSnapshot(snapshot *hedge.DebianSnapshot) *repositoryHandler {
+	packages := func(_ context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		if pkgs, ok := snapshot.Packages[snapshotPackagesKey(args.Component, args.Architecture)]; ok {
+			return pkgs, nil
+		}
+		return &hedge.DebianPackages{}, nil
+	}
+	return &repositoryHandler{
+		name:     rh.name,
+		keys:     rh.keys,
+		validFor: rh.validFor,
+		snapshot: snapshot,
+		release: func(context.Context, LoadReleaseArgs) (*hedge.DebianRelease, error) {
+			return snapshot.Release, nil
+		},
+		packages: packages,
+		// Files that are not in the snapshot are refused if upstream has them, like the live repository:
+		upstream:        rh.liveUpstream(),
+		upstreamSources: rh.liveUpstreamSources(),
+		pool:            rh.pool,
+		policy:          rh.policy,
+		// Content policies still apply, as they may have changed since the snapshot:
+		contentPolicy: rh.contentPolicy,
+	}
+}
+
+// liveUpstream loads upstream packages with the live release, instead of the release recorded in a snapshot.
+// Indices the live release doesn't have are empty.
+func (rh *repositoryHandler) liveUpstream() cached.Function[LoadPackagesArgs, *hedge.DebianPackages] {
+	return func(ctx context.Context, args LoadPackagesArgs) (*hedge.DebianPackages, error) {
+		release, err := rh.liveRelease(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !contains(release.Components, string(args.Component)) || !contains(release.Architectures, string(args.Architecture)) {
+			return &hedge.DebianPackages{}, nil
+		}
+		args.Release = release
+		return rh.upstream(ctx, args)
+	}
+}
+
+// liveUpstreamSources is liveUpstream for source packages. Returns nil if the repository doesn't serve them.
+func (rh *repositoryHandler) liveUpstreamSources() cached.Function[LoadSourcesArgs, *hedge.DebianSources] {
+	if rh.upstreamSources == nil {
+		return nil
+	}
+	return func(ctx context.Context, args LoadSourcesArgs) (*hedge.DebianSources, error) {
+		release, err := rh.liveRelease(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !contains(release.Components, string(args.Component)) {
+			return &hedge.DebianSources{}, nil
+		}
+		args.Release = release
+		return rh.upstreamSources(ctx, args)
+	}
+}
+
+// liveRelease loads the release of the live repository, as it is served.
+func (rh *repositoryHandler) liveRelease(ctx context.Context) (*hedge.DebianRelease, error) {
+	release, err := rh.release(ctx, rh.releaseArgs)
+	if err != nil {
+		return nil, err
+	}
+	if release == nil {
+		return nil, fmt.Errorf("remote release not found")
+	}
+	return servedRelease(release), nil
+}
+
+// HandleSnapshots lists the IDs of a repository's snapshots, one per line.
+func (h Handler) HandleSnapshots(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	rh := h.suite(req.PathVars["repository"], req.PathVars, "suite")
+	if rh == nil {
+		return &hedge.HttpResponse{
+			StatusCode: http.StatusNotFound,
+		}, nil
+	}
+	ids, err := h.snapshots.IDs(ctx, rh.name)
+	if err != nil {
+		return nil, err
+	}
+	var body strings.Builder
+	for _, id := range ids {
+		body.WriteString(id)
+		body.WriteString("\n")
+	}
+	return &hedge.HttpResponse{
+		ContentType: "text/plain",
+		Body:        []byte(body.String()),
+	}, nil
+}
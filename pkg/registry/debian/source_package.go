@@ -0,0 +1,270 @@
+package debian
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// Source packages are listed in `{component}/source/Sources` indices, for `deb-src` lines.
+// Reference: https://wiki.debian.org/DebianRepository/Format#A.22Sources.22_Indices
+
+func SourceFromParagraph(graph Paragraph) (*hedge.DebianSource, error) {
+	src := hedge.DebianSource{
+		Name:       graph["Package"],
+		Version:    graph["Version"],
+		Maintainer: graph["Maintainer"],
+		Format:     graph["Format"],
+		Directory:  graph["Directory"],
+		Section:    graph["Section"],
+		Priority:   graph["Priority"],
+		Homepage:   graph["Homepage"],
+	}
+	for _, b := range strings.Split(graph["Binary"], ",") {
+		if b = strings.TrimSpace(b); b != "" {
+			src.Binaries = append(src.Binaries, b)
+		}
+	}
+	src.Architectures = strings.Fields(graph["Architecture"])
+
+	// Files are listed with MD5 in Files, and with SHA256 in Checksums-Sha256:
+	files := map[string]*hedge.DebianSource_File{}
+	fileNamed := func(name string) *hedge.DebianSource_File {
+		if f, ok := files[name]; ok {
+			return f
+		}
+		f := &hedge.DebianSource_File{Name: name}
+		files[name] = f
+		src.Files = append(src.Files, f)
+		return f
+	}
+	for _, key := range []string{"Checksums-Sha256", "Files"} {
+		for _, line := range strings.Split(graph[key], "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 {
+				continue
+			}
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("invalid %s: %q", key, line)
+			}
+			digest, err := hex.DecodeString(fields[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s digest: %q", key, line)
+			}
+			size, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s size: %q", key, line)
+			}
+			f := fileNamed(fields[2])
+			if f.Size != 0 && f.Size != size {
+				return nil, fmt.Errorf("conflicting sizes for %s", f.Name)
+			}
+			f.Size = size
+			if key == "Files" {
+				f.Md5Sum = digest
+			} else {
+				f.Sha256 = digest
+			}
+		}
+	}
+
+	for k, v := range graph {
+		switch k {
+		case "Package", "Binary", "Version", "Maintainer", "Architecture", "Format", "Directory", "Section", "Priority", "Homepage", "Files", "Checksums-Sha256":
+			// Mapped above
+		default:
+			if src.ExtraFields == nil {
+				src.ExtraFields = map[string]string{}
+			}
+			src.ExtraFields[k] = v
+		}
+	}
+	return &src, nil
+}
+
+func ParagraphFromSource(src *hedge.DebianSource) Paragraph {
+	md5s := make([]string, 0, len(src.Files))
+	shas := make([]string, 0, len(src.Files))
+	for _, f := range src.Files {
+		if f.Md5Sum != nil {
+			md5s = append(md5s, fmt.Sprintf("%x %d %s", f.Md5Sum, f.Size, f.Name))
+		}
+		if f.Sha256 != nil {
+			shas = append(shas, fmt.Sprintf("%x %d %s", f.Sha256, f.Size, f.Name))
+		}
+	}
+	graph := Paragraph{
+		"Package":          src.Name,
+		"Binary":           strings.Join(src.Binaries, ", "),
+		"Version":          src.Version,
+		"Maintainer":       src.Maintainer,
+		"Architecture":     strings.Join(src.Architectures, " "),
+		"Format":           src.Format,
+		"Directory":        src.Directory,
+		"Section":          src.Section,
+		"Priority":         src.Priority,
+		"Homepage":         src.Homepage,
+		"Files":            strings.Join(md5s, "\n"),
+		"Checksums-Sha256": strings.Join(shas, "\n"),
+	}
+	// Known fields take precedence over extra fields:
+	for k, v := range src.ExtraFields {
+		if _, ok := graph[k]; !ok {
+			graph[k] = v
+		}
+	}
+	return graph
+}
+
+func (p Parser) Sources(ctx context.Context, in io.Reader) ([]*hedge.DebianSource, error) {
+	_, span := p.tracer.Start(ctx, "debian.Parser.Sources")
+	defer span.End()
+
+	var srcs []*hedge.DebianSource
+	if err := eachParagraph(in, func(graph Paragraph) error {
+		src, err := SourceFromParagraph(graph)
+		if err != nil {
+			return fmt.Errorf("parsing source: %w", err)
+		}
+		srcs = append(srcs, src)
+		return nil
+	}); err != nil {
+		return nil, observability.CaptureError(span, err)
+	}
+	span.SetAttributes(attrPackageCount(len(srcs)))
+	return srcs, nil
+}
+
+func writeSourcesFile(w io.Writer, sources []*hedge.DebianSource) error {
+	graphs := make([]Paragraph, 0, len(sources))
+	for _, src := range sources {
+		graphs = append(graphs, ParagraphFromSource(src))
+	}
+	return WriteControlFile(w, graphs...)
+}
+
+// binarySource identifies the source package that built a binary package.
+type binarySource struct{ name, version string }
+
+func sourceOfBinary(pkg *hedge.DebianPackage) binarySource {
+	// Source is empty if it matches the binary, and includes the version if it differs from the binary, like binNMUs:
+	if pkg.Source == "" {
+		return binarySource{name: pkg.Name, version: pkg.Version}
+	}
+	name, version, ok := strings.Cut(pkg.Source, " ")
+	if !ok {
+		return binarySource{name: name, version: pkg.Version}
+	}
+	return binarySource{name: name, version: strings.Trim(version, "()")}
+}
+
+// sourcesFiltered allows the upstream sources that built an allowed binary package, or are allowed by policy.
+func sourcesFiltered(tracer trace.Tracer, upstream cached.Function[LoadSourcesArgs, *hedge.DebianSources], packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages], pred filter.Predicate[*hedge.DebianSource]) cached.Function[LoadSourcesArgs, *hedge.DebianSources] {
+	return func(ctx context.Context, args LoadSourcesArgs) (*hedge.DebianSources, error) {
+		ctx, span := tracer.Start(ctx, "debian.sourcesFiltered", trace.WithAttributes(attrComponent(string(args.Component))))
+		defer span.End()
+
+		srcs, err := upstream(ctx, args)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+		built := map[binarySource]struct{}{}
+		for _, arch := range args.Release.Architectures {
+			pkgs, err := packages(ctx, LoadPackagesArgs{
+				Release:      args.Release,
+				Component:    args.Component,
+				Architecture: Architecture(arch),
+			})
+			if err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			for _, pkg := range pkgs.Packages {
+				built[sourceOfBinary(pkg)] = struct{}{}
+			}
+		}
+
+		var filtered []*hedge.DebianSource
+		for _, src := range srcs.Sources {
+			if _, ok := built[binarySource{name: src.Name, version: src.Version}]; ok {
+				filtered = append(filtered, src)
+				continue
+			}
+			if pred == nil {
+				continue
+			}
+			if ok, err := pred(ctx, src); err != nil {
+				return nil, observability.CaptureError(span, fmt.Errorf("filtering sources: %w", err))
+			} else if ok {
+				filtered = append(filtered, src)
+			}
+		}
+		span.SetAttributes(attrPackageCount(len(filtered)))
+		return &hedge.DebianSources{Sources: filtered}, nil
+	}
+}
+
+// sourcesServedFromPool rewrites the Directory of sources to the repository's pool, like servedFromPool.
+func sourcesServedFromPool(repo string, wrapped cached.Function[LoadSourcesArgs, *hedge.DebianSources]) cached.Function[LoadSourcesArgs, *hedge.DebianSources] {
+	prefix := path.Join("dists", repo)
+	return func(ctx context.Context, args LoadSourcesArgs) (*hedge.DebianSources, error) {
+		srcs, err := wrapped(ctx, args)
+		if err != nil {
+			return nil, err
+		}
+		served := make([]*hedge.DebianSource, 0, len(srcs.Sources))
+		for _, src := range srcs.Sources {
+			src := proto.Clone(src).(*hedge.DebianSource)
+			src.Directory = path.Join(prefix, src.Directory)
+			served = append(served, src)
+		}
+		return &hedge.DebianSources{Sources: served}, nil
+	}
+}
+
+// findSourceFile returns the file of a source package in the pool, or nil if no source has the file.
+// Files without a SHA256 can't be verified, so they are never found.
+func findSourceFile(ctx context.Context, sources cached.Function[LoadSourcesArgs, *hedge.DebianSources], release *hedge.DebianRelease, filename string) (*hedge.DebianSource_File, error) {
+	if sources == nil {
+		return nil, nil
+	}
+	dir, name := path.Split(filename)
+	dir = path.Clean(dir)
+	for _, c := range release.Components {
+		srcs, err := sources(ctx, LoadSourcesArgs{Release: release, Component: Component(c)})
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range srcs.Sources {
+			if src.Directory != dir {
+				continue
+			}
+			for _, f := range src.Files {
+				if f.Name == name && len(f.Sha256) > 0 {
+					return f, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// HandleSources serves the Sources index of a component, optionally by hash.
+func (h Handler) HandleSources(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	dir := fmt.Sprintf("%s/source", req.PathVars["component"])
+	if _, byHash := req.PathVars["digest"]; byHash {
+		return h.serveRenderedByHash(ctx, req, dir)
+	}
+	return h.serveRendered(ctx, req, dir+"/Sources", CompressionFromExtension(req.PathVars["compression"]))
+}
@@ -0,0 +1,151 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/filter"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const testSources = `Package: vim
+Binary: vim-common, vim-gui-common, vim, vim-tiny
+Version: 2:8.2.2434-3+deb11u1
+Maintainer: Debian Vim Maintainers <team+vim@tracker.debian.org>
+Build-Depends: debhelper-compat (= 12), libacl1-dev [linux-any], libgpm-dev [linux-any] <!pkg.vim.noX>
+Architecture: any all
+Standards-Version: 4.5.0
+Format: 3.0 (quilt)
+Files:
+ 31aa5bb97d93b2d6e5b5f0e4a4bbbc5e 2969 vim_8.2.2434-3+deb11u1.dsc
+ 3b7b9a5e1b0c1d3cbf1c86f7bca0c1a8 14997744 vim_8.2.2434.orig.tar.gz
+Package-List:
+ vim deb editors optional arch=any
+ vim-common deb editors important arch=all
+Checksums-Sha256:
+ e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855 2969 vim_8.2.2434-3+deb11u1.dsc
+ 4f3b1d2e0c9a8b7f6e5d4c3b2a1f0e9d8c7b6a5f4e3d2c1b0a9f8e7d6c5b4a39 14997744 vim_8.2.2434.orig.tar.gz
+Homepage: https://www.vim.org/
+Directory: pool/main/v/vim
+Priority: source
+Section: editors
+`
+
+func TestParser_Sources(t *testing.T) {
+	srcs, err := debian.NewParser(observability.NoopTracer).Sources(context.Background(), strings.NewReader(testSources))
+	require.NoError(t, err)
+	require.Len(t, srcs, 1)
+	src := srcs[0]
+	assert.Equal(t, "vim", src.Name)
+	assert.Equal(t, "2:8.2.2434-3+deb11u1", src.Version)
+	assert.Equal(t, []string{"vim-common", "vim-gui-common", "vim", "vim-tiny"}, src.Binaries)
+	assert.Equal(t, []string{"any", "all"}, src.Architectures)
+	assert.Equal(t, "pool/main/v/vim", src.Directory)
+	require.Len(t, src.Files, 2)
+	assert.Equal(t, "vim_8.2.2434-3+deb11u1.dsc", src.Files[0].Name)
+	assert.Equal(t, uint64(2969), src.Files[0].Size)
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", fmt.Sprintf("%x", src.Files[0].Sha256))
+	assert.Equal(t, "31aa5bb97d93b2d6e5b5f0e4a4bbbc5e", fmt.Sprintf("%x", src.Files[0].Md5Sum))
+	assert.Equal(t, "debhelper-compat (= 12), libacl1-dev [linux-any], libgpm-dev [linux-any] <!pkg.vim.noX>", src.ExtraFields["Build-Depends"])
+	assert.Equal(t, "vim deb editors optional arch=any\nvim-common deb editors important arch=all", src.ExtraFields["Package-List"])
+
+	// Rendered sources parse to the same source:
+	var buf bytes.Buffer
+	require.NoError(t, debian.WriteControlFile(&buf, debian.ParagraphFromSource(src)))
+	graphs, err := debian.ParseControlFile(&buf)
+	require.NoError(t, err)
+	require.Len(t, graphs, 1)
+	parsed, err := debian.SourceFromParagraph(graphs[0])
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(src, parsed))
+}
+
+func TestHandler_Sources(t *testing.T) {
+	mirror := newTestMirror(t)
+	policies := map[string]string{
+		"testpkg.cue":  `name: "testpkg"`,
+		"othersrc.cue": `name: "othersrc"`,
+	}
+	newSourcesHandler := func(t *testing.T, sourcePolicies *filter.Config) http.Handler {
+		repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+		repoCfg.SourcePackages = true
+		repoCfg.SourcePolicies = sourcePolicies
+		return newTestHandler(t, repoCfg, policies)
+	}
+	sources := func(t *testing.T, h http.Handler) []*hedge.DebianSource {
+		t.Helper()
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		digest, ok := release.Digests["main/source/Sources.gz"]
+		require.True(t, ok)
+		res := get(t, h, "/debian/dists/test/"+digest.Path)
+		require.Equal(t, http.StatusOK, res.Code)
+		actualDigest := sha256.Sum256(res.Body.Bytes())
+		assert.Equal(t, digest.Sha256Sum, actualDigest[:])
+
+		res = get(t, h, "/debian/dists/test/main/source/Sources")
+		require.Equal(t, http.StatusOK, res.Code)
+		srcs, err := debian.NewParser(observability.NoopTracer).Sources(context.Background(), res.Body)
+		require.NoError(t, err)
+		return srcs
+	}
+
+	t.Run("allowed binaries", func(t *testing.T) {
+		h := newSourcesHandler(t, nil)
+		srcs := sources(t, h)
+		require.Len(t, srcs, 1)
+		assert.Equal(t, "testpkg", srcs[0].Name)
+		assert.Equal(t, "dists/test/pool/main/t/testpkg", srcs[0].Directory)
+
+		for _, f := range srcs[0].Files {
+			res := get(t, h, "/debian/"+srcs[0].Directory+"/"+f.Name)
+			require.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, mirror.files["/pool/main/t/testpkg/"+f.Name], res.Body.Bytes())
+		}
+
+		res := get(t, h, "/debian/dists/test/pool/main/o/othersrc/othersrc_2.0.dsc")
+		assert.Equal(t, http.StatusForbidden, res.Code)
+		res = get(t, h, "/debian/dists/test/pool/main/o/othersrc/missing.dsc")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+
+	t.Run("source policies", func(t *testing.T) {
+		h := newSourcesHandler(t, &filter.Config{AnyOf: []string{"othersrc.cue"}})
+		var names []string
+		for _, src := range sources(t, h) {
+			names = append(names, src.Name)
+		}
+		assert.ElementsMatch(t, []string{"testpkg", "othersrc"}, names)
+		res := get(t, h, "/debian/dists/test/pool/main/o/othersrc/othersrc_2.0.dsc")
+		assert.Equal(t, http.StatusOK, res.Code)
+	})
+
+	t.Run("verified", func(t *testing.T) {
+		tampered := newTestMirror(t)
+		tampered.SetFile("/"+testSourcePath, []byte("tampered"))
+		repoCfg := testRepositoryConfig(tampered.URL, tampered.PubKey, "testpkg.cue")
+		repoCfg.SourcePackages = true
+		h := newTestHandler(t, repoCfg, policies)
+		res := get(t, h, "/debian/dists/test/"+testSourcePath)
+		assert.Equal(t, http.StatusInternalServerError, res.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		h := newTestHandler(t, testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue"), policies)
+		release := getRelease(t, h, "/debian/dists/test/InRelease")
+		assert.NotContains(t, release.Digests, "main/source/Sources.gz")
+		res := get(t, h, "/debian/dists/test/main/source/Sources")
+		assert.Equal(t, http.StatusNotFound, res.Code)
+		res = get(t, h, "/debian/dists/test/"+testSourcePath)
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
@@ -0,0 +1,111 @@
+package debian_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+func TestHandler_Suites(t *testing.T) {
+	// The mirror also publishes "next", with a newer testpkg:
+	mirror := newTestMirror(t)
+	next := newTestMirror(t)
+	next.SetPackages(t, "main", bytes.Replace(next.packages["main"], []byte("Version: 1.2.3\n"), []byte("Version: 1.2.4\n"), 1))
+	for fn, b := range next.files {
+		if strings.HasPrefix(fn, "/dists/test/") {
+			mirror.SetFile("/dists/next/"+strings.TrimPrefix(fn, "/dists/test/"), b)
+		}
+	}
+
+	repoCfg := testRepositoryConfig(mirror.URL, mirror.PubKey, "testpkg.cue")
+	repoCfg.Suites = []debian.SuiteConfig{
+		{Codename: "current", Suite: "stable", Release: "test"},
+		{Codename: "next", Suite: "testing"},
+	}
+	h := newTestHandler(t, repoCfg, map[string]string{"testpkg.cue": `name: "testpkg"`})
+	packages := func(t *testing.T, suite string) string {
+		t.Helper()
+		res := get(t, h, fmt.Sprintf("/debian/dists/test/%s/main/binary-amd64/Packages", suite))
+		require.Equal(t, http.StatusOK, res.Code)
+		pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+		require.NoError(t, err)
+		require.Len(t, pkgs, 1)
+		return pkgs[0].Version
+	}
+
+	// Suites are served by codename and suite:
+	current := getRelease(t, h, "/debian/dists/test/current/InRelease")
+	assert.Equal(t, current.Digests, getRelease(t, h, "/debian/dists/test/stable/InRelease").Digests)
+	assert.Equal(t, "1.2.3", packages(t, "current"))
+	assert.Equal(t, "1.2.3", packages(t, "stable"))
+	assert.Equal(t, "1.2.4", packages(t, "next"))
+	assert.Equal(t, "1.2.4", packages(t, "testing"))
+
+	// Each suite has its own pool, and every suite is signed by the repository's keys:
+	res := get(t, h, "/debian/dists/test/next/main/binary-amd64/Packages")
+	pkgs, err := debian.NewParser(observability.NoopTracer).Packages(context.Background(), res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "dists/test/next/"+testDebPath, pkgs[0].Filename)
+	assert.Equal(t, http.StatusOK, get(t, h, "/debian/"+pkgs[0].Filename).Code)
+	assert.Equal(t, http.StatusOK, get(t, h, "/debian/keys/test.asc").Code)
+
+	for _, path := range []string{
+		"/debian/dists/test/InRelease",
+		"/debian/dists/test/main/binary-amd64/Packages",
+		"/debian/dists/test/unstable/InRelease",
+	} {
+		assert.Equal(t, http.StatusNotFound, get(t, h, path).Code, path)
+	}
+
+	t.Run("snapshots", func(t *testing.T) {
+		res := get(t, h, "/debian/snapshots/test/testing")
+		require.Equal(t, http.StatusOK, res.Code)
+		ids := strings.Fields(res.Body.String())
+		require.Len(t, ids, 1)
+		res = get(t, h, fmt.Sprintf("/debian/snapshots/test/next/%s/dists/test/next/main/binary-amd64/Packages", ids[0]))
+		require.Equal(t, http.StatusOK, res.Code)
+		assert.Contains(t, res.Body.String(), "Version: 1.2.4\n")
+		res = get(t, h, fmt.Sprintf("/debian/snapshots/test/next/%s/dists/test/current/InRelease", ids[0]))
+		assert.Equal(t, http.StatusNotFound, res.Code)
+	})
+}
+
+func TestNewHandler_InvalidSuites(t *testing.T) {
+	for label, tc := range map[string]struct {
+		suites []debian.SuiteConfig
+		merged bool
+	}{
+		"no codename":    {suites: []debian.SuiteConfig{{Suite: "stable"}}},
+		"duplicate":      {suites: []debian.SuiteConfig{{Codename: "bullseye", Suite: "stable"}, {Codename: "bookworm", Suite: "stable"}}},
+		"alias codename": {suites: []debian.SuiteConfig{{Codename: "bullseye"}, {Codename: "bookworm", Suite: "bullseye"}}},
+		"pool":           {suites: []debian.SuiteConfig{{Codename: "pool"}}},
+		"slash":          {suites: []debian.SuiteConfig{{Codename: "bullseye/updates"}}},
+		"merged sources": {suites: []debian.SuiteConfig{{Codename: "bullseye"}}, merged: true},
+	} {
+		t.Run(label, func(t *testing.T) {
+			repoCfg := testRepositoryConfig("http://localhost", "", "testpkg.cue")
+			repoCfg.Suites = tc.suites
+			if tc.merged {
+				repoCfg.Sources = []debian.SourceConfig{repoCfg.Source}
+				repoCfg.Source = debian.SourceConfig{}
+			}
+			storage := cached.InMemory[string, []byte]()
+			_, err := debian.NewHandler(base.NewCachedMux(observability.NoopTracer, storage), observability.NoopTracer, storage, &http.Client{}, registry.EcosystemConfig{
+				Repositories: map[string]registry.RepositoryConfig{"test": repoCfg},
+				Policies:     map[string]string{"testpkg.cue": `name: "testpkg"`},
+			})
+			assert.Error(t, err)
+		})
+	}
+}
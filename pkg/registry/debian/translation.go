@@ -0,0 +1,114 @@
+package debian
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/thepwagner/hedge/pkg/cached"
+	"github.com/thepwagner/hedge/pkg/observability"
+	"github.com/thepwagner/hedge/pkg/registry/base"
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Translation indices hold the long descriptions of packages, for localized descriptions.
+// Reference: https://wiki.debian.org/DebianRepository/Format#Translation_indices
+
+func (p Parser) Translations(ctx context.Context, in io.Reader) (*hedge.DebianTranslations, error) {
+	_, span := p.tracer.Start(ctx, "debian.Parser.Translations")
+	defer span.End()
+
+	// Paragraphs are kept as they were read, so descriptions are served with their formatting:
+	var translations hedge.DebianTranslations
+	for r := NewControlReader(in); ; {
+		graph, err := r.nextRaw()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, observability.CaptureError(span, fmt.Errorf("reading translations: %w", err))
+		}
+		if name := translationName(graph); name != "" {
+			translations.Entries = append(translations.Entries, &hedge.DebianTranslations_Entry{Name: name, Paragraph: graph})
+		}
+	}
+	span.SetAttributes(attrPackageCount(len(translations.Entries)))
+	return &translations, nil
+}
+
+// translationName returns the Package field of a Translation paragraph.
+func translationName(graph string) string {
+	for _, line := range strings.Split(graph, "\n") {
+		if strings.HasPrefix(line, "Package:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Package:"))
+		}
+	}
+	return ""
+}
+
+// WriteTranslationFile renders a Translation index.
+func WriteTranslationFile(w io.Writer, translations *hedge.DebianTranslations) error {
+	bw := bufio.NewWriter(w)
+	for i, entry := range translations.Entries {
+		if i > 0 {
+			if err := bw.WriteByte('\n'); err != nil {
+				return err
+			}
+		}
+		if _, err := bw.WriteString(entry.Paragraph); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// translationsFiltered removes descriptions of packages that are not in the filtered Packages indices of any
+// architecture from the upstream Translation index.
+func translationsFiltered(tracer trace.Tracer, upstream cached.Function[LoadTranslationsArgs, *hedge.DebianTranslations], packages cached.Function[LoadPackagesArgs, *hedge.DebianPackages]) cached.Function[LoadTranslationsArgs, *hedge.DebianTranslations] {
+	return func(ctx context.Context, args LoadTranslationsArgs) (*hedge.DebianTranslations, error) {
+		ctx, span := tracer.Start(ctx, "debian.translationsFiltered", trace.WithAttributes(attrComponent(string(args.Component)), attrLanguage(args.Language)))
+		defer span.End()
+
+		allowed := map[string]struct{}{}
+		for _, arch := range args.Release.Architectures {
+			names, err := packageNames(ctx, packages, LoadPackagesArgs{
+				Release:      args.Release,
+				Component:    args.Component,
+				Architecture: Architecture(arch),
+			})
+			if err != nil {
+				return nil, observability.CaptureError(span, err)
+			}
+			for name := range names {
+				allowed[name] = struct{}{}
+			}
+		}
+		translations, err := upstream(ctx, args)
+		if err != nil {
+			return nil, observability.CaptureError(span, err)
+		}
+
+		var filtered hedge.DebianTranslations
+		for _, entry := range translations.Entries {
+			if _, ok := allowed[entry.Name]; ok {
+				filtered.Entries = append(filtered.Entries, entry)
+			}
+		}
+		span.SetAttributes(attrPackageCount(len(filtered.Entries)))
+		return &filtered, nil
+	}
+}
+
+// HandleTranslation serves the Translation index of a component and language, or any of a component's Translation
+// indices by hash.
+func (h Handler) HandleTranslation(ctx context.Context, req base.HttpRequest) (*hedge.HttpResponse, error) {
+	dir := fmt.Sprintf("%s/i18n", req.PathVars["component"])
+	if _, byHash := req.PathVars["digest"]; byHash {
+		return h.serveRenderedByHash(ctx, req, dir)
+	}
+	fn := fmt.Sprintf("%s/Translation-%s", dir, req.PathVars["language"])
+	return h.serveRendered(ctx, req, fn, CompressionFromExtension(req.PathVars["compression"]))
+}
@@ -0,0 +1,206 @@
+package debian
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thepwagner/hedge/proto/hedge/v1"
+)
+
+// Version is a parsed Debian package version, `[epoch:]upstream_version[-debian_revision]`.
+// Reference: https://www.debian.org/doc/debian-policy/ch-controlfields.html#version
+type Version struct {
+	Epoch    int
+	Upstream string
+	Revision string
+}
+
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return v, fmt.Errorf("version is empty")
+	}
+	if strings.ContainsAny(s, " \t\n") {
+		return v, fmt.Errorf("version %q has embedded spaces", s)
+	}
+
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		epoch, err := strconv.Atoi(s[:i])
+		if err != nil || epoch < 0 {
+			return v, fmt.Errorf("version %q has an invalid epoch", s)
+		}
+		v.Epoch = epoch
+		s = s[i+1:]
+	}
+	if i := strings.LastIndexByte(s, '-'); i >= 0 {
+		v.Revision = s[i+1:]
+		if v.Revision == "" {
+			return v, fmt.Errorf("version %q has an empty revision", s)
+		}
+		for _, c := range v.Revision {
+			if !isRevisionChar(c) {
+				return v, fmt.Errorf("version %q has invalid revision character %q", s, c)
+			}
+		}
+		s = s[:i]
+	}
+	if s == "" {
+		return v, fmt.Errorf("version has an empty upstream version")
+	}
+	for _, c := range s {
+		if !isVersionChar(c) {
+			return v, fmt.Errorf("version %q has invalid character %q", s, c)
+		}
+	}
+	v.Upstream = s
+	return v, nil
+}
+
+// isVersionChar reports whether c may appear in an upstream version. Hyphens are only possible before a revision,
+// and colons are not allowed as the epoch has taken the first.
+func isVersionChar(c rune) bool {
+	return isDigit(c) || isLetter(c) || strings.ContainsRune(".+-~", c)
+}
+
+// isRevisionChar reports whether c may appear in a Debian revision.
+func isRevisionChar(c rune) bool {
+	return isDigit(c) || isLetter(c) || strings.ContainsRune(".+~", c)
+}
+
+func (v Version) String() string {
+	var sb strings.Builder
+	if v.Epoch != 0 {
+		fmt.Fprintf(&sb, "%d:", v.Epoch)
+	}
+	sb.WriteString(v.Upstream)
+	if v.Revision != "" {
+		sb.WriteString("-")
+		sb.WriteString(v.Revision)
+	}
+	return sb.String()
+}
+
+// Compare orders versions like dpkg, returning -1, 0 or 1 like strings.Compare.
+func (v Version) Compare(other Version) int {
+	switch {
+	case v.Epoch < other.Epoch:
+		return -1
+	case v.Epoch > other.Epoch:
+		return 1
+	}
+	if c := compareVersionPart(v.Upstream, other.Upstream); c != 0 {
+		return c
+	}
+	return compareVersionPart(v.Revision, other.Revision)
+}
+
+// CompareVersions parses and compares two version strings.
+func CompareVersions(a, b string) (int, error) {
+	va, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb), nil
+}
+
+// satisfiesRelation reports whether a package version satisfies the version constraint of a relation.
+// An empty version, like from an unversioned Provides, only satisfies unversioned relations.
+func satisfiesRelation(rel *hedge.DebianRelation, version string) bool {
+	if rel.VersionOperator == "" {
+		return true
+	}
+	if version == "" {
+		return false
+	}
+	c, err := CompareVersions(version, rel.Version)
+	if err != nil {
+		return false
+	}
+	switch rel.VersionOperator {
+	case "<<":
+		return c < 0
+	case "<=", "<":
+		return c <= 0
+	case "=":
+		return c == 0
+	case ">=", ">":
+		return c >= 0
+	case ">>":
+		return c > 0
+	default:
+		return false
+	}
+}
+
+// compareVersionPart is dpkg's verrevcmp: alternating non-digit and digit runs are compared in turn.
+// Non-digits are compared by versionCharOrder, digits numerically.
+func compareVersionPart(a, b string) int {
+	for a != "" || b != "" {
+		for (a != "" && !isDigit(rune(a[0]))) || (b != "" && !isDigit(rune(b[0]))) {
+			ac, bc := versionCharOrder(a), versionCharOrder(b)
+			if ac != bc {
+				return sign(ac - bc)
+			}
+			a, b = a[1:], b[1:]
+		}
+
+		// Compare digit runs numerically, ignoring leading zeros:
+		a = strings.TrimLeft(a, "0")
+		b = strings.TrimLeft(b, "0")
+		an, bn := digitPrefix(a), digitPrefix(b)
+		if len(an) != len(bn) {
+			return sign(len(an) - len(bn))
+		}
+		if c := strings.Compare(an, bn); c != 0 {
+			return c
+		}
+		a, b = a[len(an):], b[len(bn):]
+	}
+	return 0
+}
+
+// versionCharOrder sorts `~` before everything (even the end of the string), then letters before other characters.
+func versionCharOrder(s string) int {
+	if s == "" {
+		return 0
+	}
+	c := rune(s[0])
+	switch {
+	case isDigit(c):
+		return 0
+	case isLetter(c):
+		return int(c)
+	case c == '~':
+		return -1
+	default:
+		return int(c) + 256
+	}
+}
+
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(rune(s[i])) {
+		i++
+	}
+	return s[:i]
+}
+
+func isDigit(c rune) bool  { return c >= '0' && c <= '9' }
+func isLetter(c rune) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+
+func sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,89 @@
+package debian_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thepwagner/hedge/pkg/registry/debian"
+)
+
+func TestParseVersion(t *testing.T) {
+	cases := map[string]debian.Version{
+		"1.0":                  {Upstream: "1.0"},
+		"7.66+dfsg-6":          {Upstream: "7.66+dfsg", Revision: "6"},
+		"1:1.1.4":              {Epoch: 1, Upstream: "1.1.4"},
+		"1:1.2.11.dfsg-2":      {Epoch: 1, Upstream: "1.2.11.dfsg", Revision: "2"},
+		"1.1.1n-0+deb11u3":     {Upstream: "1.1.1n", Revision: "0+deb11u3"},
+		"2:8.2.2434-3+deb11u1": {Epoch: 2, Upstream: "8.2.2434", Revision: "3+deb11u1"},
+		"1.0-rc1-2":            {Upstream: "1.0-rc1", Revision: "2"},
+		"2.0~beta1":            {Upstream: "2.0~beta1"},
+	}
+	for in, expected := range cases {
+		t.Run(in, func(t *testing.T) {
+			v, err := debian.ParseVersion(in)
+			require.NoError(t, err)
+			assert.Equal(t, expected, v)
+			assert.Equal(t, in, v.String())
+		})
+	}
+}
+
+func TestParseVersion_Invalid(t *testing.T) {
+	for _, in := range []string{"", "1.0 2", "a:1.0", "-1:1.0", "1.0-", ":1.0", "1.0_2", "1:2:3", "1:2.0:1-1", "1.0-1:2", "1.0-1_2", "1.0-a/b"} {
+		_, err := debian.ParseVersion(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	// Each version is less than the following version:
+	ordered := []string{
+		"0:0.9",
+		"1.0~~",
+		"1.0~~a",
+		"1.0~",
+		"1.0",
+		"1.0-1",
+		"1.0-1+b1",
+		"1.0-2",
+		"1.0-10",
+		"1.0a",
+		"1.0+dfsg",
+		"1.00.1",
+		"1.1",
+		"1.1.1n-0+deb11u2",
+		"1.1.1n-0+deb11u3",
+		"1.1.1o",
+		"1.9",
+		"1.10",
+		"1:0.1",
+		"2:0.1",
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			c, err := debian.CompareVersions(a, b)
+			require.NoError(t, err)
+			switch {
+			case i < j:
+				assert.Equal(t, -1, c, "%s < %s", a, b)
+			case i > j:
+				assert.Equal(t, 1, c, "%s > %s", a, b)
+			default:
+				assert.Equal(t, 0, c, "%s = %s", a, b)
+			}
+		}
+	}
+}
+
+func TestCompareVersions_Equivalent(t *testing.T) {
+	for a, b := range map[string]string{
+		"1.0":   "0:1.0",
+		"1.01":  "1.1",
+		"1.0-0": "1.0-00",
+	} {
+		c, err := debian.CompareVersions(a, b)
+		require.NoError(t, err)
+		assert.Equal(t, 0, c, "%s = %s", a, b)
+	}
+}
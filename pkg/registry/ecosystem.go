@@ -5,7 +5,6 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/thepwagner/hedge/pkg/cached"
-	"github.com/thepwagner/hedge/pkg/filter"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -30,7 +29,8 @@ type HandlerArgs struct {
 type RepositoryConfig interface {
 	Name() string
 	SetName(string)
-	FilterConfig() filter.Config
+	// PolicyNames lists every policy the repository refers to, so they can be loaded.
+	PolicyNames() []string
 }
 
 // EcosystemConfig is configuration for an ecosystem.
@@ -78,6 +78,9 @@ func newDistLoader(tracer trace.Tracer, client *http.Client, cfgDir string, cfg
 		return nil, fmt.Errorf("no package sources")
 	}
 
+	if len(cfg.Policies.Versions) > 0 {
+		return nil, fmt.Errorf("npm policies don't support versions")
+	}
 	pred, err := filter.CueConfigToPredicate[PackageVersion](filepath.Join(cfgDir, "npm", "policies"), cfg.Policies)
 	if err != nil {
 		return nil, err
@@ -111,7 +111,7 @@ func loadRepositories(ep registry.EcosystemProvider, repoDir string) (map[string
 func loadPolicyFiles(repos map[string]registry.RepositoryConfig, policyDir string) (map[string]string, error) {
 	policies := make(map[string]string)
 	for _, repoCfg := range repos {
-		for _, policyName := range repoCfg.FilterConfig().PolicyNames() {
+		for _, policyName := range repoCfg.PolicyNames() {
 			b, err := os.ReadFile(filepath.Join(policyDir, policyName))
 			if err != nil {
 				return nil, err
@@ -2,6 +2,7 @@ package server_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -15,11 +16,30 @@ func TestLoadConfig(t *testing.T) {
 
 	debCfg, ok := cfg.Ecosystems[debian.Ecosystem]
 	require.True(t, ok)
-	assert.Len(t, debCfg.Repositories, 2)
+	assert.Len(t, debCfg.Repositories, 4)
 
 	bullseyeCfg, ok := debCfg.Repositories["bullseye"].(*debian.RepositoryConfig)
 	require.True(t, ok)
 	assert.Equal(t, "https://debian.mirror.rafal.ca/debian/", bullseyeCfg.Source.Upstream.URL)
+	assert.True(t, bullseyeCfg.IncludeDependencies)
+	assert.Equal(t, 72*time.Hour, bullseyeCfg.ValidFor)
+	assert.True(t, bullseyeCfg.SourcePackages)
+	assert.True(t, bullseyeCfg.Contents)
+	assert.Equal(t, []string{"en"}, bullseyeCfg.Translations)
+
+	mergedCfg, ok := debCfg.Repositories["merged"].(*debian.RepositoryConfig)
+	require.True(t, ok)
+	assert.Equal(t, debian.ConflictsByVersion, mergedCfg.Conflicts)
+	require.Len(t, mergedCfg.Sources, 3)
+	assert.Equal(t, "security", mergedCfg.Sources[1].Name)
+	assert.Equal(t, 10, mergedCfg.Sources[1].Priority)
+	assert.Equal(t, []string{"cosign.cue"}, mergedCfg.Sources[2].Policies.AnyOf)
+	assert.ElementsMatch(t, []string{"vim.cue", "cosign.cue"}, mergedCfg.PolicyNames())
+
+	suitesCfg, ok := debCfg.Repositories["debian"].(*debian.RepositoryConfig)
+	require.True(t, ok)
+	require.Len(t, suitesCfg.Suites, 3)
+	assert.Equal(t, debian.SuiteConfig{Codename: "bullseye-updates", Suite: "oldstable-updates"}, suitesCfg.Suites[1])
 
 	assert.Contains(t, debCfg.Policies["nethack.cue"], "Games")
 }
@@ -70,7 +70,9 @@ func newMuxRouter(ctx context.Context, tp *sdktrace.TracerProvider, cfg Config)
 	storage := cached.InRedis(cfg.RedisAddr, tp)
 
 	bh := base.NewCachedMux(tracer, storage)
-	debian.NewHandler(bh, tracer, storage, client, cfg.Ecosystems[debian.Ecosystem])
+	if _, err := debian.NewHandler(bh, tracer, storage, client, cfg.Ecosystems[debian.Ecosystem]); err != nil {
+		return nil, err
+	}
 
 	// for _, ep := range Ecosystems(tracer, client, storage) {
 	// 	eco := ep.Ecosystem()
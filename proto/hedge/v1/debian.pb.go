@@ -42,6 +42,12 @@ type DebianRelease struct {
 	Digests                     map[string]*DebianRelease_DigestedFile `protobuf:"bytes,13,rep,name=digests,proto3" json:"digests,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	MirrorUrl                   string                                 `protobuf:"bytes,14,opt,name=mirror_url,json=mirrorUrl,proto3" json:"mirror_url,omitempty"`
 	Dist                        string                                 `protobuf:"bytes,15,opt,name=dist,proto3" json:"dist,omitempty"`
+	// extra_fields are fields hedge doesn't recognize, passed through as-is.
+	ExtraFields map[string]string `protobuf:"bytes,16,rep,name=extra_fields,json=extraFields,proto3" json:"extra_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// mirror_urls serve the release in order of preference, mirror_url is the mirror the release was loaded from.
+	MirrorUrls []string `protobuf:"bytes,17,rep,name=mirror_urls,json=mirrorUrls,proto3" json:"mirror_urls,omitempty"`
+	// valid_until is when clients should stop trusting the release.
+	ValidUntil *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
 }
 
 func (x *DebianRelease) Reset() {
@@ -181,43 +187,70 @@ func (x *DebianRelease) GetDist() string {
 	return ""
 }
 
+func (x *DebianRelease) GetExtraFields() map[string]string {
+	if x != nil {
+		return x.ExtraFields
+	}
+	return nil
+}
+
+func (x *DebianRelease) GetMirrorUrls() []string {
+	if x != nil {
+		return x.MirrorUrls
+	}
+	return nil
+}
+
+func (x *DebianRelease) GetValidUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return nil
+}
+
 // DebianPackage is a .deb
 type DebianPackage struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Source        string   `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
-	Version       string   `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
-	InstalledSize uint64   `protobuf:"varint,4,opt,name=installed_size,json=installedSize,proto3" json:"installed_size,omitempty"`
-	Maintainer    string   `protobuf:"bytes,5,opt,name=maintainer,proto3" json:"maintainer,omitempty"`
-	Depends       []string `protobuf:"bytes,6,rep,name=depends,proto3" json:"depends,omitempty"`
-	PreDepends    []string `protobuf:"bytes,7,rep,name=pre_depends,json=preDepends,proto3" json:"pre_depends,omitempty"`
-	Recommends    []string `protobuf:"bytes,18,rep,name=recommends,proto3" json:"recommends,omitempty"`
-	Conflicts     []string `protobuf:"bytes,19,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
-	Replaces      []string `protobuf:"bytes,20,rep,name=replaces,proto3" json:"replaces,omitempty"`
-	Suggests      []string `protobuf:"bytes,21,rep,name=suggests,proto3" json:"suggests,omitempty"`
-	Enhances      []string `protobuf:"bytes,22,rep,name=enhances,proto3" json:"enhances,omitempty"`
-	Breaks        []string `protobuf:"bytes,23,rep,name=breaks,proto3" json:"breaks,omitempty"`
-	Provides      []string `protobuf:"bytes,24,rep,name=provides,proto3" json:"provides,omitempty"`
-	Section       string   `protobuf:"bytes,8,opt,name=section,proto3" json:"section,omitempty"`
-	Tags          []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
-	Description   string   `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
-	Homepage      string   `protobuf:"bytes,11,opt,name=homepage,proto3" json:"homepage,omitempty"`
-	Priority      string   `protobuf:"bytes,12,opt,name=priority,proto3" json:"priority,omitempty"`
-	Architecture  string   `protobuf:"bytes,13,opt,name=architecture,proto3" json:"architecture,omitempty"`
-	Filename      string   `protobuf:"bytes,14,opt,name=filename,proto3" json:"filename,omitempty"`
-	Size          uint64   `protobuf:"varint,15,opt,name=size,proto3" json:"size,omitempty"`
-	Multiarch     string   `protobuf:"bytes,25,opt,name=multiarch,proto3" json:"multiarch,omitempty"`
-	RubyVersions  []string `protobuf:"bytes,26,rep,name=ruby_versions,json=rubyVersions,proto3" json:"ruby_versions,omitempty"`
-	PythonVersion string   `protobuf:"bytes,27,opt,name=python_version,json=pythonVersion,proto3" json:"python_version,omitempty"`
-	LuaVersions   []string `protobuf:"bytes,31,rep,name=lua_versions,json=luaVersions,proto3" json:"lua_versions,omitempty"`
-	Essential     bool     `protobuf:"varint,28,opt,name=essential,proto3" json:"essential,omitempty"`
-	Important     bool     `protobuf:"varint,29,opt,name=important,proto3" json:"important,omitempty"`
-	Protected     bool     `protobuf:"varint,30,opt,name=protected,proto3" json:"protected,omitempty"`
-	Md5Sum        []byte   `protobuf:"bytes,16,opt,name=md5sum,proto3" json:"md5sum,omitempty"`
-	Sha256        []byte   `protobuf:"bytes,17,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Name          string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source        string              `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	Version       string              `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	InstalledSize uint64              `protobuf:"varint,4,opt,name=installed_size,json=installedSize,proto3" json:"installed_size,omitempty"`
+	Maintainer    string              `protobuf:"bytes,5,opt,name=maintainer,proto3" json:"maintainer,omitempty"`
+	Depends       []*DebianDependency `protobuf:"bytes,32,rep,name=depends,proto3" json:"depends,omitempty"`
+	PreDepends    []*DebianDependency `protobuf:"bytes,33,rep,name=pre_depends,json=preDepends,proto3" json:"pre_depends,omitempty"`
+	Recommends    []*DebianDependency `protobuf:"bytes,34,rep,name=recommends,proto3" json:"recommends,omitempty"`
+	Conflicts     []*DebianDependency `protobuf:"bytes,35,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
+	Replaces      []*DebianDependency `protobuf:"bytes,36,rep,name=replaces,proto3" json:"replaces,omitempty"`
+	Suggests      []*DebianDependency `protobuf:"bytes,37,rep,name=suggests,proto3" json:"suggests,omitempty"`
+	Enhances      []*DebianDependency `protobuf:"bytes,38,rep,name=enhances,proto3" json:"enhances,omitempty"`
+	Breaks        []*DebianDependency `protobuf:"bytes,39,rep,name=breaks,proto3" json:"breaks,omitempty"`
+	Provides      []*DebianRelation   `protobuf:"bytes,40,rep,name=provides,proto3" json:"provides,omitempty"`
+	Section       string              `protobuf:"bytes,8,opt,name=section,proto3" json:"section,omitempty"`
+	Tags          []string            `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	Description   string              `protobuf:"bytes,10,opt,name=description,proto3" json:"description,omitempty"`
+	Homepage      string              `protobuf:"bytes,11,opt,name=homepage,proto3" json:"homepage,omitempty"`
+	Priority      string              `protobuf:"bytes,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	Architecture  string              `protobuf:"bytes,13,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Filename      string              `protobuf:"bytes,14,opt,name=filename,proto3" json:"filename,omitempty"`
+	Size          uint64              `protobuf:"varint,15,opt,name=size,proto3" json:"size,omitempty"`
+	Multiarch     string              `protobuf:"bytes,25,opt,name=multiarch,proto3" json:"multiarch,omitempty"`
+	RubyVersions  []string            `protobuf:"bytes,26,rep,name=ruby_versions,json=rubyVersions,proto3" json:"ruby_versions,omitempty"`
+	PythonVersion string              `protobuf:"bytes,27,opt,name=python_version,json=pythonVersion,proto3" json:"python_version,omitempty"`
+	LuaVersions   []string            `protobuf:"bytes,31,rep,name=lua_versions,json=luaVersions,proto3" json:"lua_versions,omitempty"`
+	Essential     bool                `protobuf:"varint,28,opt,name=essential,proto3" json:"essential,omitempty"`
+	Important     bool                `protobuf:"varint,29,opt,name=important,proto3" json:"important,omitempty"`
+	Protected     bool                `protobuf:"varint,30,opt,name=protected,proto3" json:"protected,omitempty"`
+	Md5Sum        []byte              `protobuf:"bytes,16,opt,name=md5sum,proto3" json:"md5sum,omitempty"`
+	Sha256        []byte              `protobuf:"bytes,17,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	// extra_fields are fields hedge doesn't recognize, passed through as-is.
+	ExtraFields map[string]string `protobuf:"bytes,41,rep,name=extra_fields,json=extraFields,proto3" json:"extra_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// files are the contents of the package, if it was parsed from a .deb.
+	Files []*DebianFile `protobuf:"bytes,42,rep,name=files,proto3" json:"files,omitempty"`
+	// maintainer_scripts are keyed by name, like `postinst`. Triggers are included as `triggers`.
+	MaintainerScripts map[string]*DebianScript `protobuf:"bytes,43,rep,name=maintainer_scripts,json=maintainerScripts,proto3" json:"maintainer_scripts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *DebianPackage) Reset() {
@@ -287,63 +320,63 @@ func (x *DebianPackage) GetMaintainer() string {
 	return ""
 }
 
-func (x *DebianPackage) GetDepends() []string {
+func (x *DebianPackage) GetDepends() []*DebianDependency {
 	if x != nil {
 		return x.Depends
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetPreDepends() []string {
+func (x *DebianPackage) GetPreDepends() []*DebianDependency {
 	if x != nil {
 		return x.PreDepends
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetRecommends() []string {
+func (x *DebianPackage) GetRecommends() []*DebianDependency {
 	if x != nil {
 		return x.Recommends
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetConflicts() []string {
+func (x *DebianPackage) GetConflicts() []*DebianDependency {
 	if x != nil {
 		return x.Conflicts
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetReplaces() []string {
+func (x *DebianPackage) GetReplaces() []*DebianDependency {
 	if x != nil {
 		return x.Replaces
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetSuggests() []string {
+func (x *DebianPackage) GetSuggests() []*DebianDependency {
 	if x != nil {
 		return x.Suggests
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetEnhances() []string {
+func (x *DebianPackage) GetEnhances() []*DebianDependency {
 	if x != nil {
 		return x.Enhances
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetBreaks() []string {
+func (x *DebianPackage) GetBreaks() []*DebianDependency {
 	if x != nil {
 		return x.Breaks
 	}
 	return nil
 }
 
-func (x *DebianPackage) GetProvides() []string {
+func (x *DebianPackage) GetProvides() []*DebianRelation {
 	if x != nil {
 		return x.Provides
 	}
@@ -469,17 +502,46 @@ func (x *DebianPackage) GetSha256() []byte {
 	return nil
 }
 
-// DebianPackages is a collection of DebianPackage
-type DebianPackages struct {
+func (x *DebianPackage) GetExtraFields() map[string]string {
+	if x != nil {
+		return x.ExtraFields
+	}
+	return nil
+}
+
+func (x *DebianPackage) GetFiles() []*DebianFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *DebianPackage) GetMaintainerScripts() map[string]*DebianScript {
+	if x != nil {
+		return x.MaintainerScripts
+	}
+	return nil
+}
+
+// DebianFile is an entry of a package's data archive. Directories are not included.
+type DebianFile struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Packages []*DebianPackage `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+	// path is absolute, like `/usr/bin/vim`.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// mode includes the setuid, setgid and sticky bits.
+	Mode   uint32 `protobuf:"varint,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	Size   uint64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Setuid bool   `protobuf:"varint,4,opt,name=setuid,proto3" json:"setuid,omitempty"`
+	Setgid bool   `protobuf:"varint,5,opt,name=setgid,proto3" json:"setgid,omitempty"`
+	// link is the target of a symbolic or hard link.
+	Link string `protobuf:"bytes,6,opt,name=link,proto3" json:"link,omitempty"`
 }
 
-func (x *DebianPackages) Reset() {
-	*x = DebianPackages{}
+func (x *DebianFile) Reset() {
+	*x = DebianFile{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_hedge_v1_debian_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -487,13 +549,13 @@ func (x *DebianPackages) Reset() {
 	}
 }
 
-func (x *DebianPackages) String() string {
+func (x *DebianFile) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebianPackages) ProtoMessage() {}
+func (*DebianFile) ProtoMessage() {}
 
-func (x *DebianPackages) ProtoReflect() protoreflect.Message {
+func (x *DebianFile) ProtoReflect() protoreflect.Message {
 	mi := &file_hedge_v1_debian_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -505,46 +567,81 @@ func (x *DebianPackages) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebianPackages.ProtoReflect.Descriptor instead.
-func (*DebianPackages) Descriptor() ([]byte, []int) {
+// Deprecated: Use DebianFile.ProtoReflect.Descriptor instead.
+func (*DebianFile) Descriptor() ([]byte, []int) {
 	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *DebianPackages) GetPackages() []*DebianPackage {
+func (x *DebianFile) GetPath() string {
 	if x != nil {
-		return x.Packages
+		return x.Path
 	}
-	return nil
+	return ""
 }
 
-type DebianRelease_DigestedFile struct {
+func (x *DebianFile) GetMode() uint32 {
+	if x != nil {
+		return x.Mode
+	}
+	return 0
+}
+
+func (x *DebianFile) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DebianFile) GetSetuid() bool {
+	if x != nil {
+		return x.Setuid
+	}
+	return false
+}
+
+func (x *DebianFile) GetSetgid() bool {
+	if x != nil {
+		return x.Setgid
+	}
+	return false
+}
+
+func (x *DebianFile) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+// DebianScript is a maintainer script from a package's control archive.
+type DebianScript struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Path      string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Size      uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
-	Md5Sum    []byte `protobuf:"bytes,3,opt,name=md5sum,proto3" json:"md5sum,omitempty"`
-	Sha256Sum []byte `protobuf:"bytes,4,opt,name=sha256sum,proto3" json:"sha256sum,omitempty"`
+	// sha256 is hex-encoded, to be readable in policies.
+	Sha256 string `protobuf:"bytes,1,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	Size   uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
 }
 
-func (x *DebianRelease_DigestedFile) Reset() {
-	*x = DebianRelease_DigestedFile{}
+func (x *DebianScript) Reset() {
+	*x = DebianScript{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_hedge_v1_debian_proto_msgTypes[4]
+		mi := &file_hedge_v1_debian_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *DebianRelease_DigestedFile) String() string {
+func (x *DebianScript) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DebianRelease_DigestedFile) ProtoMessage() {}
+func (*DebianScript) ProtoMessage() {}
 
-func (x *DebianRelease_DigestedFile) ProtoReflect() protoreflect.Message {
-	mi := &file_hedge_v1_debian_proto_msgTypes[4]
+func (x *DebianScript) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -555,242 +652,1968 @@ func (x *DebianRelease_DigestedFile) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DebianRelease_DigestedFile.ProtoReflect.Descriptor instead.
-func (*DebianRelease_DigestedFile) Descriptor() ([]byte, []int) {
-	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{0, 1}
+// Deprecated: Use DebianScript.ProtoReflect.Descriptor instead.
+func (*DebianScript) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *DebianRelease_DigestedFile) GetPath() string {
+func (x *DebianScript) GetSha256() string {
 	if x != nil {
-		return x.Path
+		return x.Sha256
 	}
 	return ""
 }
 
-func (x *DebianRelease_DigestedFile) GetSize() uint64 {
+func (x *DebianScript) GetSize() uint64 {
 	if x != nil {
 		return x.Size
 	}
 	return 0
 }
 
-func (x *DebianRelease_DigestedFile) GetMd5Sum() []byte {
+// DebianRelation refers to another package, like `libc6:any (>= 2.17)`.
+type DebianRelation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name            string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ArchQualifier   string   `protobuf:"bytes,2,opt,name=arch_qualifier,json=archQualifier,proto3" json:"arch_qualifier,omitempty"`
+	VersionOperator string   `protobuf:"bytes,3,opt,name=version_operator,json=versionOperator,proto3" json:"version_operator,omitempty"`
+	Version         string   `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	Architectures   []string `protobuf:"bytes,5,rep,name=architectures,proto3" json:"architectures,omitempty"`
+}
+
+func (x *DebianRelation) Reset() {
+	*x = DebianRelation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianRelation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianRelation) ProtoMessage() {}
+
+func (x *DebianRelation) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianRelation.ProtoReflect.Descriptor instead.
+func (*DebianRelation) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DebianRelation) GetName() string {
 	if x != nil {
-		return x.Md5Sum
+		return x.Name
 	}
-	return nil
+	return ""
 }
 
-func (x *DebianRelease_DigestedFile) GetSha256Sum() []byte {
+func (x *DebianRelation) GetArchQualifier() string {
 	if x != nil {
-		return x.Sha256Sum
+		return x.ArchQualifier
+	}
+	return ""
+}
+
+func (x *DebianRelation) GetVersionOperator() string {
+	if x != nil {
+		return x.VersionOperator
+	}
+	return ""
+}
+
+func (x *DebianRelation) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DebianRelation) GetArchitectures() []string {
+	if x != nil {
+		return x.Architectures
 	}
 	return nil
 }
 
-var File_hedge_v1_debian_proto protoreflect.FileDescriptor
+// DebianDependency is satisfied by any of its alternatives, like `default-mta | mail-transport-agent`.
+type DebianDependency struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_hedge_v1_debian_proto_rawDesc = []byte{
-	0x0a, 0x15, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x65, 0x62, 0x69, 0x61,
-	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76,
-	0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
-	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x22, 0xf2, 0x05, 0x0a, 0x0d, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c,
-	0x65, 0x61, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f,
-	0x62, 0x79, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61,
-	0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x42, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x0d,
-	0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72,
-	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x6c, 0x6f, 0x67, 0x73,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x6c, 0x6f,
-	0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x64, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x64, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e,
-	0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03,
-	0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e,
-	0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
-	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x20,
-	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x44, 0x0a, 0x1f, 0x6e, 0x6f, 0x5f, 0x73, 0x75, 0x70,
-	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65,
-	0x63, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x1b, 0x6e, 0x6f, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x41, 0x72, 0x63,
-	0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x0a, 0x06,
-	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72,
-	0x69, 0x67, 0x69, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18, 0x0b, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18,
-	0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
-	0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2e, 0x44,
-	0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x64, 0x69, 0x67,
-	0x65, 0x73, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x75,
-	0x72, 0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72,
-	0x55, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x74, 0x1a, 0x60, 0x0a, 0x0c, 0x44, 0x69, 0x67, 0x65, 0x73,
-	0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3a, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65,
-	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73,
-	0x65, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x6c, 0x0a, 0x0c, 0x44, 0x69, 0x67,
-	0x65, 0x73, 0x74, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74,
-	0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a,
-	0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x68, 0x61,
-	0x32, 0x35, 0x36, 0x73, 0x75, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x68,
-	0x61, 0x32, 0x35, 0x36, 0x73, 0x75, 0x6d, 0x22, 0x90, 0x07, 0x0a, 0x0d, 0x44, 0x65, 0x62, 0x69,
-	0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a,
-	0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73,
-	0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x25, 0x0a, 0x0e, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c,
-	0x65, 0x64, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x61,
-	0x69, 0x6e, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x69, 0x6e,
-	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64,
-	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73,
-	0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x18,
-	0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64,
-	0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x73, 0x18,
-	0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64,
-	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x18, 0x13,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x12,
-	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73,
-	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x15, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x73,
-	0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x6e, 0x68, 0x61, 0x6e,
-	0x63, 0x65, 0x73, 0x18, 0x16, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x65, 0x6e, 0x68, 0x61, 0x6e,
-	0x63, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x73, 0x18, 0x17, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x70,
-	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x18, 0x18, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70,
-	0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52,
-	0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x70,
-	0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f, 0x6d, 0x65, 0x70,
-	0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18,
-	0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12,
-	0x22, 0x0a, 0x0c, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74,
-	0x75, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73,
-	0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x61, 0x72, 0x63, 0x68,
-	0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x75, 0x6c, 0x74, 0x69, 0x61, 0x72, 0x63,
-	0x68, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x75, 0x62, 0x79, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x75, 0x62, 0x79, 0x56, 0x65,
-	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x70, 0x79, 0x74, 0x68, 0x6f, 0x6e,
-	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
-	0x70, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x21, 0x0a,
-	0x0c, 0x6c, 0x75, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x1f, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x75, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73,
-	0x12, 0x1c, 0x0a, 0x09, 0x65, 0x73, 0x73, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x18, 0x1c, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x73, 0x73, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x12, 0x1c,
-	0x0a, 0x09, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e, 0x74, 0x18, 0x1d, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x09, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
-	0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x64,
-	0x35, 0x73, 0x75, 0x6d, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6d, 0x64, 0x35, 0x73,
-	0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x11, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x45, 0x0a, 0x0e, 0x44, 0x65,
-	0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x33, 0x0a, 0x08,
-	0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
-	0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e,
-	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
-	0x73, 0x42, 0x79, 0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76,
-	0x31, 0x42, 0x0b, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01,
-	0x5a, 0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x68, 0x65,
-	0x70, 0x77, 0x61, 0x67, 0x6e, 0x65, 0x72, 0x2f, 0x68, 0x65, 0x64, 0x67, 0x65, 0xa2, 0x02, 0x03,
-	0x48, 0x58, 0x58, 0xaa, 0x02, 0x08, 0x48, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02,
-	0x08, 0x48, 0x65, 0x64, 0x67, 0x65, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x48, 0x65, 0x64, 0x67,
-	0x65, 0x5c, 0x56, 0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61,
-	0xea, 0x02, 0x09, 0x48, 0x65, 0x64, 0x67, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	Alternatives []*DebianRelation `protobuf:"bytes,1,rep,name=alternatives,proto3" json:"alternatives,omitempty"`
 }
 
-var (
-	file_hedge_v1_debian_proto_rawDescOnce sync.Once
-	file_hedge_v1_debian_proto_rawDescData = file_hedge_v1_debian_proto_rawDesc
-)
+func (x *DebianDependency) Reset() {
+	*x = DebianDependency{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_hedge_v1_debian_proto_rawDescGZIP() []byte {
-	file_hedge_v1_debian_proto_rawDescOnce.Do(func() {
-		file_hedge_v1_debian_proto_rawDescData = protoimpl.X.CompressGZIP(file_hedge_v1_debian_proto_rawDescData)
-	})
-	return file_hedge_v1_debian_proto_rawDescData
+func (x *DebianDependency) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_hedge_v1_debian_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
-var file_hedge_v1_debian_proto_goTypes = []interface{}{
-	(*DebianRelease)(nil),              // 0: hedge.v1.DebianRelease
-	(*DebianPackage)(nil),              // 1: hedge.v1.DebianPackage
-	(*DebianPackages)(nil),             // 2: hedge.v1.DebianPackages
-	nil,                                // 3: hedge.v1.DebianRelease.DigestsEntry
-	(*DebianRelease_DigestedFile)(nil), // 4: hedge.v1.DebianRelease.DigestedFile
-	(*timestamppb.Timestamp)(nil),      // 5: google.protobuf.Timestamp
+func (*DebianDependency) ProtoMessage() {}
+
+func (x *DebianDependency) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_hedge_v1_debian_proto_depIdxs = []int32{
-	5, // 0: hedge.v1.DebianRelease.date:type_name -> google.protobuf.Timestamp
-	3, // 1: hedge.v1.DebianRelease.digests:type_name -> hedge.v1.DebianRelease.DigestsEntry
-	1, // 2: hedge.v1.DebianPackages.packages:type_name -> hedge.v1.DebianPackage
-	4, // 3: hedge.v1.DebianRelease.DigestsEntry.value:type_name -> hedge.v1.DebianRelease.DigestedFile
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+
+// Deprecated: Use DebianDependency.ProtoReflect.Descriptor instead.
+func (*DebianDependency) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{5}
 }
 
-func init() { file_hedge_v1_debian_proto_init() }
-func file_hedge_v1_debian_proto_init() {
-	if File_hedge_v1_debian_proto != nil {
-		return
+func (x *DebianDependency) GetAlternatives() []*DebianRelation {
+	if x != nil {
+		return x.Alternatives
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_hedge_v1_debian_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DebianRelease); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_hedge_v1_debian_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DebianPackage); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_hedge_v1_debian_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DebianPackages); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return nil
+}
+
+// DebianPackages is a collection of DebianPackage
+type DebianPackages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Packages []*DebianPackage `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *DebianPackages) Reset() {
+	*x = DebianPackages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianPackages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianPackages) ProtoMessage() {}
+
+func (x *DebianPackages) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianPackages.ProtoReflect.Descriptor instead.
+func (*DebianPackages) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DebianPackages) GetPackages() []*DebianPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+// DebianSnapshot is an immutable copy of a filtered repository.
+type DebianSnapshot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Created *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created,proto3" json:"created,omitempty"`
+	Release *DebianRelease         `protobuf:"bytes,3,opt,name=release,proto3" json:"release,omitempty"`
+	// packages are keyed by "{component}/binary-{arch}".
+	Packages map[string]*DebianPackages `protobuf:"bytes,4,rep,name=packages,proto3" json:"packages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// digest identifies the packages, to detect changes.
+	Digest []byte `protobuf:"bytes,5,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *DebianSnapshot) Reset() {
+	*x = DebianSnapshot{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSnapshot) ProtoMessage() {}
+
+func (x *DebianSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSnapshot.ProtoReflect.Descriptor instead.
+func (*DebianSnapshot) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DebianSnapshot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DebianSnapshot) GetCreated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *DebianSnapshot) GetRelease() *DebianRelease {
+	if x != nil {
+		return x.Release
+	}
+	return nil
+}
+
+func (x *DebianSnapshot) GetPackages() map[string]*DebianPackages {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+func (x *DebianSnapshot) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+// DebianSnapshots indexes the snapshots of a repository, oldest first.
+type DebianSnapshots struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Snapshots []*DebianSnapshots_Entry `protobuf:"bytes,1,rep,name=snapshots,proto3" json:"snapshots,omitempty"`
+}
+
+func (x *DebianSnapshots) Reset() {
+	*x = DebianSnapshots{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSnapshots) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSnapshots) ProtoMessage() {}
+
+func (x *DebianSnapshots) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSnapshots.ProtoReflect.Descriptor instead.
+func (*DebianSnapshots) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DebianSnapshots) GetSnapshots() []*DebianSnapshots_Entry {
+	if x != nil {
+		return x.Snapshots
+	}
+	return nil
+}
+
+// DebianPackagesDiffs is the PDiff history of a Packages file, so clients can update with patches.
+type DebianPackagesDiffs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// current_sha256 and current_size identify the latest Packages file, which the next patch applies to.
+	CurrentSha256 []byte `protobuf:"bytes,1,opt,name=current_sha256,json=currentSha256,proto3" json:"current_sha256,omitempty"`
+	CurrentSize   uint64 `protobuf:"varint,2,opt,name=current_size,json=currentSize,proto3" json:"current_size,omitempty"`
+	// patches are ed scripts between consecutive Packages files, oldest first.
+	Patches []*DebianPackagesDiffs_Patch `protobuf:"bytes,3,rep,name=patches,proto3" json:"patches,omitempty"`
+}
+
+func (x *DebianPackagesDiffs) Reset() {
+	*x = DebianPackagesDiffs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianPackagesDiffs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianPackagesDiffs) ProtoMessage() {}
+
+func (x *DebianPackagesDiffs) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianPackagesDiffs.ProtoReflect.Descriptor instead.
+func (*DebianPackagesDiffs) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DebianPackagesDiffs) GetCurrentSha256() []byte {
+	if x != nil {
+		return x.CurrentSha256
+	}
+	return nil
+}
+
+func (x *DebianPackagesDiffs) GetCurrentSize() uint64 {
+	if x != nil {
+		return x.CurrentSize
+	}
+	return 0
+}
+
+func (x *DebianPackagesDiffs) GetPatches() []*DebianPackagesDiffs_Patch {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+// DebianRetainedPackages are the package versions an index has allowed, so they can be served after upstream
+// replaces them.
+type DebianRetainedPackages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*DebianRetainedPackages_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *DebianRetainedPackages) Reset() {
+	*x = DebianRetainedPackages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianRetainedPackages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianRetainedPackages) ProtoMessage() {}
+
+func (x *DebianRetainedPackages) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianRetainedPackages.ProtoReflect.Descriptor instead.
+func (*DebianRetainedPackages) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DebianRetainedPackages) GetEntries() []*DebianRetainedPackages_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// DebianSource is a source package, from a Sources index.
+type DebianSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name          string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Binaries      []string `protobuf:"bytes,2,rep,name=binaries,proto3" json:"binaries,omitempty"`
+	Version       string   `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Maintainer    string   `protobuf:"bytes,4,opt,name=maintainer,proto3" json:"maintainer,omitempty"`
+	Architectures []string `protobuf:"bytes,5,rep,name=architectures,proto3" json:"architectures,omitempty"`
+	Format        string   `protobuf:"bytes,6,opt,name=format,proto3" json:"format,omitempty"`
+	// directory contains the source package's files, relative to the repository root.
+	Directory string               `protobuf:"bytes,7,opt,name=directory,proto3" json:"directory,omitempty"`
+	Section   string               `protobuf:"bytes,8,opt,name=section,proto3" json:"section,omitempty"`
+	Priority  string               `protobuf:"bytes,9,opt,name=priority,proto3" json:"priority,omitempty"`
+	Homepage  string               `protobuf:"bytes,10,opt,name=homepage,proto3" json:"homepage,omitempty"`
+	Files     []*DebianSource_File `protobuf:"bytes,11,rep,name=files,proto3" json:"files,omitempty"`
+	// extra_fields are fields hedge doesn't recognize, passed through as-is.
+	// Build-Depends is passed through, as it may contain architecture restrictions and build profiles.
+	ExtraFields map[string]string `protobuf:"bytes,12,rep,name=extra_fields,json=extraFields,proto3" json:"extra_fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *DebianSource) Reset() {
+	*x = DebianSource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSource) ProtoMessage() {}
+
+func (x *DebianSource) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSource.ProtoReflect.Descriptor instead.
+func (*DebianSource) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DebianSource) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DebianSource) GetBinaries() []string {
+	if x != nil {
+		return x.Binaries
+	}
+	return nil
+}
+
+func (x *DebianSource) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *DebianSource) GetMaintainer() string {
+	if x != nil {
+		return x.Maintainer
+	}
+	return ""
+}
+
+func (x *DebianSource) GetArchitectures() []string {
+	if x != nil {
+		return x.Architectures
+	}
+	return nil
+}
+
+func (x *DebianSource) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *DebianSource) GetDirectory() string {
+	if x != nil {
+		return x.Directory
+	}
+	return ""
+}
+
+func (x *DebianSource) GetSection() string {
+	if x != nil {
+		return x.Section
+	}
+	return ""
+}
+
+func (x *DebianSource) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *DebianSource) GetHomepage() string {
+	if x != nil {
+		return x.Homepage
+	}
+	return ""
+}
+
+func (x *DebianSource) GetFiles() []*DebianSource_File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *DebianSource) GetExtraFields() map[string]string {
+	if x != nil {
+		return x.ExtraFields
+	}
+	return nil
+}
+
+type DebianSources struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Sources []*DebianSource `protobuf:"bytes,1,rep,name=sources,proto3" json:"sources,omitempty"`
+}
+
+func (x *DebianSources) Reset() {
+	*x = DebianSources{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSources) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSources) ProtoMessage() {}
+
+func (x *DebianSources) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSources.ProtoReflect.Descriptor instead.
+func (*DebianSources) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DebianSources) GetSources() []*DebianSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// DebianContents maps files to the packages that contain them, from a Contents-{arch} index.
+type DebianContents struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*DebianContents_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *DebianContents) Reset() {
+	*x = DebianContents{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianContents) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianContents) ProtoMessage() {}
+
+func (x *DebianContents) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianContents.ProtoReflect.Descriptor instead.
+func (*DebianContents) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DebianContents) GetEntries() []*DebianContents_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// DebianTranslations are the translated descriptions of packages, from an i18n/Translation-{language} index.
+type DebianTranslations struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Entries []*DebianTranslations_Entry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *DebianTranslations) Reset() {
+	*x = DebianTranslations{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianTranslations) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianTranslations) ProtoMessage() {}
+
+func (x *DebianTranslations) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianTranslations.ProtoReflect.Descriptor instead.
+func (*DebianTranslations) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DebianTranslations) GetEntries() []*DebianTranslations_Entry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// DebianHostedPackages are the packages uploaded to a hosted source.
+type DebianHostedPackages struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// updated changes with every upload.
+	Updated *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=updated,proto3" json:"updated,omitempty"`
+	// packages are keyed by component and architecture, like `main/binary-amd64`.
+	Packages map[string]*DebianPackages `protobuf:"bytes,2,rep,name=packages,proto3" json:"packages,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *DebianHostedPackages) Reset() {
+	*x = DebianHostedPackages{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianHostedPackages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianHostedPackages) ProtoMessage() {}
+
+func (x *DebianHostedPackages) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianHostedPackages.ProtoReflect.Descriptor instead.
+func (*DebianHostedPackages) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DebianHostedPackages) GetUpdated() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Updated
+	}
+	return nil
+}
+
+func (x *DebianHostedPackages) GetPackages() map[string]*DebianPackages {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+// DebianRendering lists the index files rendered for a repository. Each file is stored by its SHA256.
+type DebianRendering struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// files are keyed by path in the dist, like `main/binary-amd64/Packages.gz`.
+	Files map[string]*DebianRelease_DigestedFile `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *DebianRendering) Reset() {
+	*x = DebianRendering{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianRendering) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianRendering) ProtoMessage() {}
+
+func (x *DebianRendering) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianRendering.ProtoReflect.Descriptor instead.
+func (*DebianRendering) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DebianRendering) GetFiles() map[string]*DebianRelease_DigestedFile {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+type DebianRelease_DigestedFile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path      string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Size      uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Md5Sum    []byte `protobuf:"bytes,3,opt,name=md5sum,proto3" json:"md5sum,omitempty"`
+	Sha256Sum []byte `protobuf:"bytes,4,opt,name=sha256sum,proto3" json:"sha256sum,omitempty"`
+}
+
+func (x *DebianRelease_DigestedFile) Reset() {
+	*x = DebianRelease_DigestedFile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianRelease_DigestedFile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianRelease_DigestedFile) ProtoMessage() {}
+
+func (x *DebianRelease_DigestedFile) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianRelease_DigestedFile.ProtoReflect.Descriptor instead.
+func (*DebianRelease_DigestedFile) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *DebianRelease_DigestedFile) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DebianRelease_DigestedFile) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DebianRelease_DigestedFile) GetMd5Sum() []byte {
+	if x != nil {
+		return x.Md5Sum
+	}
+	return nil
+}
+
+func (x *DebianRelease_DigestedFile) GetSha256Sum() []byte {
+	if x != nil {
+		return x.Sha256Sum
+	}
+	return nil
+}
+
+type DebianSnapshots_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Digest []byte `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+}
+
+func (x *DebianSnapshots_Entry) Reset() {
+	*x = DebianSnapshots_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSnapshots_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSnapshots_Entry) ProtoMessage() {}
+
+func (x *DebianSnapshots_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSnapshots_Entry.ProtoReflect.Descriptor instead.
+func (*DebianSnapshots_Entry) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{8, 0}
+}
+
+func (x *DebianSnapshots_Entry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DebianSnapshots_Entry) GetDigest() []byte {
+	if x != nil {
+		return x.Digest
+	}
+	return nil
+}
+
+type DebianPackagesDiffs_Patch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// history_sha256 and history_size identify the Packages file the patch applies to.
+	HistorySha256 []byte `protobuf:"bytes,2,opt,name=history_sha256,json=historySha256,proto3" json:"history_sha256,omitempty"`
+	HistorySize   uint64 `protobuf:"varint,3,opt,name=history_size,json=historySize,proto3" json:"history_size,omitempty"`
+	PatchSha256   []byte `protobuf:"bytes,4,opt,name=patch_sha256,json=patchSha256,proto3" json:"patch_sha256,omitempty"`
+	PatchSize     uint64 `protobuf:"varint,5,opt,name=patch_size,json=patchSize,proto3" json:"patch_size,omitempty"`
+	// download is the gzipped patch.
+	Download []byte `protobuf:"bytes,6,opt,name=download,proto3" json:"download,omitempty"`
+}
+
+func (x *DebianPackagesDiffs_Patch) Reset() {
+	*x = DebianPackagesDiffs_Patch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianPackagesDiffs_Patch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianPackagesDiffs_Patch) ProtoMessage() {}
+
+func (x *DebianPackagesDiffs_Patch) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianPackagesDiffs_Patch.ProtoReflect.Descriptor instead.
+func (*DebianPackagesDiffs_Patch) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{9, 0}
+}
+
+func (x *DebianPackagesDiffs_Patch) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DebianPackagesDiffs_Patch) GetHistorySha256() []byte {
+	if x != nil {
+		return x.HistorySha256
+	}
+	return nil
+}
+
+func (x *DebianPackagesDiffs_Patch) GetHistorySize() uint64 {
+	if x != nil {
+		return x.HistorySize
+	}
+	return 0
+}
+
+func (x *DebianPackagesDiffs_Patch) GetPatchSha256() []byte {
+	if x != nil {
+		return x.PatchSha256
+	}
+	return nil
+}
+
+func (x *DebianPackagesDiffs_Patch) GetPatchSize() uint64 {
+	if x != nil {
+		return x.PatchSize
+	}
+	return 0
+}
+
+func (x *DebianPackagesDiffs_Patch) GetDownload() []byte {
+	if x != nil {
+		return x.Download
+	}
+	return nil
+}
+
+type DebianRetainedPackages_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Package *DebianPackage `protobuf:"bytes,1,opt,name=package,proto3" json:"package,omitempty"`
+	// removed is when the version left the upstream index, unset while upstream still has it.
+	Removed *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=removed,proto3" json:"removed,omitempty"`
+	// pinned_until is when the version's pool file is stored until, once it is pinned.
+	PinnedUntil *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=pinned_until,json=pinnedUntil,proto3" json:"pinned_until,omitempty"`
+}
+
+func (x *DebianRetainedPackages_Entry) Reset() {
+	*x = DebianRetainedPackages_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianRetainedPackages_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianRetainedPackages_Entry) ProtoMessage() {}
+
+func (x *DebianRetainedPackages_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianRetainedPackages_Entry.ProtoReflect.Descriptor instead.
+func (*DebianRetainedPackages_Entry) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{10, 0}
+}
+
+func (x *DebianRetainedPackages_Entry) GetPackage() *DebianPackage {
+	if x != nil {
+		return x.Package
+	}
+	return nil
+}
+
+func (x *DebianRetainedPackages_Entry) GetRemoved() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *DebianRetainedPackages_Entry) GetPinnedUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PinnedUntil
+	}
+	return nil
+}
+
+type DebianSource_File struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name   string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Size   uint64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Md5Sum []byte `protobuf:"bytes,3,opt,name=md5sum,proto3" json:"md5sum,omitempty"`
+	Sha256 []byte `protobuf:"bytes,4,opt,name=sha256,proto3" json:"sha256,omitempty"`
+}
+
+func (x *DebianSource_File) Reset() {
+	*x = DebianSource_File{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianSource_File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianSource_File) ProtoMessage() {}
+
+func (x *DebianSource_File) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianSource_File.ProtoReflect.Descriptor instead.
+func (*DebianSource_File) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{11, 1}
+}
+
+func (x *DebianSource_File) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DebianSource_File) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *DebianSource_File) GetMd5Sum() []byte {
+	if x != nil {
+		return x.Md5Sum
+	}
+	return nil
+}
+
+func (x *DebianSource_File) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+type DebianContents_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// packages are qualified by section, like `editors/vim`.
+	Packages []string `protobuf:"bytes,2,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *DebianContents_Entry) Reset() {
+	*x = DebianContents_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianContents_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianContents_Entry) ProtoMessage() {}
+
+func (x *DebianContents_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianContents_Entry.ProtoReflect.Descriptor instead.
+func (*DebianContents_Entry) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{13, 0}
+}
+
+func (x *DebianContents_Entry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *DebianContents_Entry) GetPackages() []string {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+type DebianTranslations_Entry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// paragraph is passed through as-is, to preserve the formatting of long descriptions.
+	Paragraph string `protobuf:"bytes,2,opt,name=paragraph,proto3" json:"paragraph,omitempty"`
+}
+
+func (x *DebianTranslations_Entry) Reset() {
+	*x = DebianTranslations_Entry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_hedge_v1_debian_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DebianTranslations_Entry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DebianTranslations_Entry) ProtoMessage() {}
+
+func (x *DebianTranslations_Entry) ProtoReflect() protoreflect.Message {
+	mi := &file_hedge_v1_debian_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DebianTranslations_Entry.ProtoReflect.Descriptor instead.
+func (*DebianTranslations_Entry) Descriptor() ([]byte, []int) {
+	return file_hedge_v1_debian_proto_rawDescGZIP(), []int{14, 0}
+}
+
+func (x *DebianTranslations_Entry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DebianTranslations_Entry) GetParagraph() string {
+	if x != nil {
+		return x.Paragraph
+	}
+	return ""
+}
+
+var File_hedge_v1_debian_proto protoreflect.FileDescriptor
+
+var file_hedge_v1_debian_proto_rawDesc = []byte{
+	0x0a, 0x15, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x65, 0x62, 0x69, 0x61,
+	0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x08, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xdd, 0x07, 0x0a, 0x0d, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c,
+	0x65, 0x61, 0x73, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x5f,
+	0x62, 0x79, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x61,
+	0x63, 0x71, 0x75, 0x69, 0x72, 0x65, 0x42, 0x79, 0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x0d,
+	0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x6c, 0x6f, 0x67, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x6c, 0x6f,
+	0x67, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x64, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x64, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e,
+	0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x2e,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x04, 0x64, 0x61, 0x74, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x12, 0x44, 0x0a, 0x1f, 0x6e, 0x6f, 0x5f, 0x73, 0x75, 0x70,
+	0x70, 0x6f, 0x72, 0x74, 0x5f, 0x66, 0x6f, 0x72, 0x5f, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65,
+	0x63, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x6c, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x1b, 0x6e, 0x6f, 0x53, 0x75, 0x70, 0x70, 0x6f, 0x72, 0x74, 0x46, 0x6f, 0x72, 0x41, 0x72, 0x63,
+	0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x18, 0x0b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x75, 0x69, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x3e, 0x0a, 0x07, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18,
+	0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2e, 0x44,
+	0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x64, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x75,
+	0x72, 0x6c, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72,
+	0x55, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x69, 0x73, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x64, 0x69, 0x73, 0x74, 0x12, 0x4b, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61,
+	0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e,
+	0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x69, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x75,
+	0x72, 0x6c, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x69, 0x72, 0x72, 0x6f,
+	0x72, 0x55, 0x72, 0x6c, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x5f, 0x75,
+	0x6e, 0x74, 0x69, 0x6c, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0a, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x55, 0x6e, 0x74,
+	0x69, 0x6c, 0x1a, 0x60, 0x0a, 0x0c, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x3a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x69, 0x67,
+	0x65, 0x73, 0x74, 0x65, 0x64, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x1a, 0x6c, 0x0a, 0x0c, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64,
+	0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6d, 0x64,
+	0x35, 0x73, 0x75, 0x6d, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x73, 0x75,
+	0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x73,
+	0x75, 0x6d, 0x22, 0x92, 0x0c, 0x0a, 0x0d, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x69, 0x6e,
+	0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x0d, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x65, 0x64, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x72, 0x12, 0x34, 0x0a, 0x07, 0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x20, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x07,
+	0x64, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x70, 0x72, 0x65, 0x5f, 0x64,
+	0x65, 0x70, 0x65, 0x6e, 0x64, 0x73, 0x18, 0x21, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x0a, 0x70, 0x72, 0x65, 0x44, 0x65, 0x70,
+	0x65, 0x6e, 0x64, 0x73, 0x12, 0x3a, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x64, 0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64,
+	0x65, 0x6e, 0x63, 0x79, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x64, 0x73,
+	0x12, 0x38, 0x0a, 0x09, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x18, 0x23, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44,
+	0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52,
+	0x09, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x72, 0x65,
+	0x70, 0x6c, 0x61, 0x63, 0x65, 0x73, 0x18, 0x24, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x73, 0x18, 0x25,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79,
+	0x52, 0x08, 0x73, 0x75, 0x67, 0x67, 0x65, 0x73, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x08, 0x65, 0x6e,
+	0x68, 0x61, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x26, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65,
+	0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x08, 0x65, 0x6e, 0x68, 0x61, 0x6e, 0x63,
+	0x65, 0x73, 0x12, 0x32, 0x0a, 0x06, 0x62, 0x72, 0x65, 0x61, 0x6b, 0x73, 0x18, 0x27, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x52, 0x06,
+	0x62, 0x72, 0x65, 0x61, 0x6b, 0x73, 0x12, 0x34, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x73, 0x18, 0x28, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73,
+	0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x09,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08,
+	0x68, 0x6f, 0x6d, 0x65, 0x70, 0x61, 0x67, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x68, 0x6f, 0x6d, 0x65, 0x70, 0x61, 0x67, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x69, 0x6f,
+	0x72, 0x69, 0x74, 0x79, 0x12, 0x22, 0x0a, 0x0c, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x0f, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6d, 0x75, 0x6c, 0x74,
+	0x69, 0x61, 0x72, 0x63, 0x68, 0x18, 0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x75, 0x6c,
+	0x74, 0x69, 0x61, 0x72, 0x63, 0x68, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x75, 0x62, 0x79, 0x5f, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x1a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x72,
+	0x75, 0x62, 0x79, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x70,
+	0x79, 0x74, 0x68, 0x6f, 0x6e, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x1b, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x70, 0x79, 0x74, 0x68, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x75, 0x61, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x1f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x75, 0x61, 0x56, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x73, 0x73, 0x65, 0x6e, 0x74, 0x69,
+	0x61, 0x6c, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x65, 0x73, 0x73, 0x65, 0x6e, 0x74,
+	0x69, 0x61, 0x6c, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e, 0x74,
+	0x18, 0x1d, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x61, 0x6e,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x18, 0x1e,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12,
+	0x4b, 0x0a, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18,
+	0x29, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x2e, 0x45,
+	0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x2a, 0x0a, 0x05,
+	0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x2a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x68, 0x65,
+	0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x46, 0x69, 0x6c,
+	0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x5d, 0x0a, 0x12, 0x6d, 0x61, 0x69, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x5f, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x18, 0x2b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x2e, 0x4d, 0x61,
+	0x69, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x6d, 0x61, 0x69, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72,
+	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x5c, 0x0a, 0x16, 0x4d, 0x61, 0x69, 0x6e, 0x74,
+	0x61, 0x69, 0x6e, 0x65, 0x72, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x62, 0x69, 0x61, 0x6e, 0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07, 0x4a, 0x04, 0x08, 0x07, 0x10,
+	0x08, 0x4a, 0x04, 0x08, 0x12, 0x10, 0x19, 0x22, 0x8c, 0x01, 0x0a, 0x0a, 0x44, 0x65, 0x62, 0x69,
+	0x61, 0x6e, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65, 0x74, 0x75, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x06, 0x73, 0x65, 0x74, 0x75, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x65,
+	0x74, 0x67, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x65, 0x74, 0x67,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6c, 0x69, 0x6e, 0x6b, 0x22, 0x3a, 0x0a, 0x0c, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e,
+	0x53, 0x63, 0x72, 0x69, 0x70, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x12,
+	0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73, 0x69,
+	0x7a, 0x65, 0x22, 0xb6, 0x01, 0x0a, 0x0e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x61, 0x72, 0x63,
+	0x68, 0x5f, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72,
+	0x12, 0x29, 0x0a, 0x10, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65,
+	0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x72,
+	0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x22, 0x50, 0x0a, 0x10, 0x44,
+	0x65, 0x62, 0x69, 0x61, 0x6e, 0x44, 0x65, 0x70, 0x65, 0x6e, 0x64, 0x65, 0x6e, 0x63, 0x79, 0x12,
+	0x3c, 0x0a, 0x0c, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0c, 0x61, 0x6c, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x74, 0x69, 0x76, 0x65, 0x73, 0x22, 0x45, 0x0a,
+	0x0e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12,
+	0x33, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62,
+	0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x73, 0x22, 0xbc, 0x02, 0x0a, 0x0e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53,
+	0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x34, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x31, 0x0a,
+	0x07, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e,
+	0x52, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x65, 0x61, 0x73, 0x65,
+	0x12, 0x42, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65,
+	0x62, 0x69, 0x61, 0x6e, 0x53, 0x6e, 0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x2e, 0x50, 0x61, 0x63,
+	0x6b, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x1a, 0x55, 0x0a, 0x0d,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
+	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
+	0x2e, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x81, 0x01, 0x0a, 0x0f, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53, 0x6e,
+	0x61, 0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x12, 0x3d, 0x0a, 0x09, 0x73, 0x6e, 0x61, 0x70, 0x73,
+	0x68, 0x6f, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x68, 0x65, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53, 0x6e, 0x61, 0x70,
+	0x73, 0x68, 0x6f, 0x74, 0x73, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x73, 0x6e, 0x61,
+	0x70, 0x73, 0x68, 0x6f, 0x74, 0x73, 0x1a, 0x2f, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x22, 0xe4, 0x02, 0x0a, 0x13, 0x44, 0x65, 0x62, 0x69,
+	0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x44, 0x69, 0x66, 0x66, 0x73, 0x12,
+	0x25, 0x0a, 0x0e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x32, 0x35,
+	0x36, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
+	0x53, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e,
+	0x74, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x3d, 0x0a, 0x07, 0x70, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x68, 0x65, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b,
+	0x61, 0x67, 0x65, 0x73, 0x44, 0x69, 0x66, 0x66, 0x73, 0x2e, 0x50, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x07, 0x70, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x1a, 0xc3, 0x01, 0x0a, 0x05, 0x50, 0x61, 0x74,
+	0x63, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x5f, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d,
+	0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x68, 0x61, 0x32, 0x35, 0x36, 0x12, 0x21, 0x0a,
+	0x0c, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x0b, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x74, 0x63, 0x68, 0x53, 0x68, 0x61,
+	0x32, 0x35, 0x36, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x70, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69,
+	0x7a, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x64, 0x6f, 0x77, 0x6e, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x8c,
+	0x02, 0x0a, 0x16, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x74, 0x61, 0x69, 0x6e, 0x65,
+	0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x40, 0x0a, 0x07, 0x65, 0x6e, 0x74,
+	0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e, 0x68, 0x65, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x74, 0x61,
+	0x69, 0x6e, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x2e, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0xaf, 0x01, 0x0a, 0x05,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x31, 0x0a, 0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52,
+	0x07, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x34, 0x0a, 0x07, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x64, 0x12, 0x3d,
+	0x0a, 0x0c, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x5f, 0x75, 0x6e, 0x74, 0x69, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x52, 0x0b, 0x70, 0x69, 0x6e, 0x6e, 0x65, 0x64, 0x55, 0x6e, 0x74, 0x69, 0x6c, 0x22, 0xc5, 0x04,
+	0x0a, 0x0c, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x69, 0x65, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x6d, 0x61, 0x69, 0x6e,
+	0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x61,
+	0x69, 0x6e, 0x74, 0x61, 0x69, 0x6e, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x61, 0x72, 0x63, 0x68,
+	0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0d, 0x61, 0x72, 0x63, 0x68, 0x69, 0x74, 0x65, 0x63, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x70, 0x61, 0x67, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x68, 0x6f,
+	0x6d, 0x65, 0x70, 0x61, 0x67, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18,
+	0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x4a, 0x0a, 0x0c, 0x65, 0x78, 0x74,
+	0x72, 0x61, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x27, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61,
+	0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x65, 0x78, 0x74, 0x72, 0x61, 0x46,
+	0x69, 0x65, 0x6c, 0x64, 0x73, 0x1a, 0x3e, 0x0a, 0x10, 0x45, 0x78, 0x74, 0x72, 0x61, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x5e, 0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6d, 0x64, 0x35, 0x73, 0x75, 0x6d, 0x12, 0x16, 0x0a,
+	0x06, 0x73, 0x68, 0x61, 0x32, 0x35, 0x36, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73,
+	0x68, 0x61, 0x32, 0x35, 0x36, 0x22, 0x41, 0x0a, 0x0d, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x07, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x22, 0x83, 0x01, 0x0a, 0x0e, 0x44, 0x65, 0x62,
+	0x69, 0x61, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x38, 0x0a, 0x07, 0x65,
+	0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x68,
+	0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x43, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x73, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e,
+	0x74, 0x72, 0x69, 0x65, 0x73, 0x1a, 0x37, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12,
+	0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x22, 0x8d,
+	0x01, 0x0a, 0x12, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x3c, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x6c, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x65, 0x6e, 0x74, 0x72,
+	0x69, 0x65, 0x73, 0x1a, 0x39, 0x0a, 0x05, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x72, 0x61, 0x67, 0x72, 0x61, 0x70, 0x68, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x72, 0x61, 0x67, 0x72, 0x61, 0x70, 0x68, 0x22, 0xed,
+	0x01, 0x0a, 0x14, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x48, 0x6f, 0x73, 0x74, 0x65, 0x64, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x07, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x48, 0x0a,
+	0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2c, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61,
+	0x6e, 0x48, 0x6f, 0x73, 0x74, 0x65, 0x64, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x2e,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x70,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x1a, 0x55, 0x0a, 0x0d, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x68, 0x65, 0x64, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xad,
+	0x01, 0x0a, 0x0f, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x69,
+	0x6e, 0x67, 0x12, 0x3a, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x24, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62,
+	0x69, 0x61, 0x6e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x69, 0x6e, 0x67, 0x2e, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x1a, 0x5e,
+	0x0a, 0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x3a,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e,
+	0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x52,
+	0x65, 0x6c, 0x65, 0x61, 0x73, 0x65, 0x2e, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x65, 0x64, 0x46,
+	0x69, 0x6c, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x79,
+	0x0a, 0x0c, 0x63, 0x6f, 0x6d, 0x2e, 0x68, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x42, 0x0b,
+	0x44, 0x65, 0x62, 0x69, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x50, 0x01, 0x5a, 0x1b, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x74, 0x68, 0x65, 0x70, 0x77, 0x61,
+	0x67, 0x6e, 0x65, 0x72, 0x2f, 0x68, 0x65, 0x64, 0x67, 0x65, 0xa2, 0x02, 0x03, 0x48, 0x58, 0x58,
+	0xaa, 0x02, 0x08, 0x48, 0x65, 0x64, 0x67, 0x65, 0x2e, 0x56, 0x31, 0xca, 0x02, 0x08, 0x48, 0x65,
+	0x64, 0x67, 0x65, 0x5c, 0x56, 0x31, 0xe2, 0x02, 0x14, 0x48, 0x65, 0x64, 0x67, 0x65, 0x5c, 0x56,
+	0x31, 0x5c, 0x47, 0x50, 0x42, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0xea, 0x02, 0x09,
+	0x48, 0x65, 0x64, 0x67, 0x65, 0x3a, 0x3a, 0x56, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_hedge_v1_debian_proto_rawDescOnce sync.Once
+	file_hedge_v1_debian_proto_rawDescData = file_hedge_v1_debian_proto_rawDesc
+)
+
+func file_hedge_v1_debian_proto_rawDescGZIP() []byte {
+	file_hedge_v1_debian_proto_rawDescOnce.Do(func() {
+		file_hedge_v1_debian_proto_rawDescData = protoimpl.X.CompressGZIP(file_hedge_v1_debian_proto_rawDescData)
+	})
+	return file_hedge_v1_debian_proto_rawDescData
+}
+
+var file_hedge_v1_debian_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
+var file_hedge_v1_debian_proto_goTypes = []interface{}{
+	(*DebianRelease)(nil),                // 0: hedge.v1.DebianRelease
+	(*DebianPackage)(nil),                // 1: hedge.v1.DebianPackage
+	(*DebianFile)(nil),                   // 2: hedge.v1.DebianFile
+	(*DebianScript)(nil),                 // 3: hedge.v1.DebianScript
+	(*DebianRelation)(nil),               // 4: hedge.v1.DebianRelation
+	(*DebianDependency)(nil),             // 5: hedge.v1.DebianDependency
+	(*DebianPackages)(nil),               // 6: hedge.v1.DebianPackages
+	(*DebianSnapshot)(nil),               // 7: hedge.v1.DebianSnapshot
+	(*DebianSnapshots)(nil),              // 8: hedge.v1.DebianSnapshots
+	(*DebianPackagesDiffs)(nil),          // 9: hedge.v1.DebianPackagesDiffs
+	(*DebianRetainedPackages)(nil),       // 10: hedge.v1.DebianRetainedPackages
+	(*DebianSource)(nil),                 // 11: hedge.v1.DebianSource
+	(*DebianSources)(nil),                // 12: hedge.v1.DebianSources
+	(*DebianContents)(nil),               // 13: hedge.v1.DebianContents
+	(*DebianTranslations)(nil),           // 14: hedge.v1.DebianTranslations
+	(*DebianHostedPackages)(nil),         // 15: hedge.v1.DebianHostedPackages
+	(*DebianRendering)(nil),              // 16: hedge.v1.DebianRendering
+	nil,                                  // 17: hedge.v1.DebianRelease.DigestsEntry
+	nil,                                  // 18: hedge.v1.DebianRelease.ExtraFieldsEntry
+	(*DebianRelease_DigestedFile)(nil),   // 19: hedge.v1.DebianRelease.DigestedFile
+	nil,                                  // 20: hedge.v1.DebianPackage.ExtraFieldsEntry
+	nil,                                  // 21: hedge.v1.DebianPackage.MaintainerScriptsEntry
+	nil,                                  // 22: hedge.v1.DebianSnapshot.PackagesEntry
+	(*DebianSnapshots_Entry)(nil),        // 23: hedge.v1.DebianSnapshots.Entry
+	(*DebianPackagesDiffs_Patch)(nil),    // 24: hedge.v1.DebianPackagesDiffs.Patch
+	(*DebianRetainedPackages_Entry)(nil), // 25: hedge.v1.DebianRetainedPackages.Entry
+	nil,                                  // 26: hedge.v1.DebianSource.ExtraFieldsEntry
+	(*DebianSource_File)(nil),            // 27: hedge.v1.DebianSource.File
+	(*DebianContents_Entry)(nil),         // 28: hedge.v1.DebianContents.Entry
+	(*DebianTranslations_Entry)(nil),     // 29: hedge.v1.DebianTranslations.Entry
+	nil,                                  // 30: hedge.v1.DebianHostedPackages.PackagesEntry
+	nil,                                  // 31: hedge.v1.DebianRendering.FilesEntry
+	(*timestamppb.Timestamp)(nil),        // 32: google.protobuf.Timestamp
+}
+var file_hedge_v1_debian_proto_depIdxs = []int32{
+	32, // 0: hedge.v1.DebianRelease.date:type_name -> google.protobuf.Timestamp
+	17, // 1: hedge.v1.DebianRelease.digests:type_name -> hedge.v1.DebianRelease.DigestsEntry
+	18, // 2: hedge.v1.DebianRelease.extra_fields:type_name -> hedge.v1.DebianRelease.ExtraFieldsEntry
+	32, // 3: hedge.v1.DebianRelease.valid_until:type_name -> google.protobuf.Timestamp
+	5,  // 4: hedge.v1.DebianPackage.depends:type_name -> hedge.v1.DebianDependency
+	5,  // 5: hedge.v1.DebianPackage.pre_depends:type_name -> hedge.v1.DebianDependency
+	5,  // 6: hedge.v1.DebianPackage.recommends:type_name -> hedge.v1.DebianDependency
+	5,  // 7: hedge.v1.DebianPackage.conflicts:type_name -> hedge.v1.DebianDependency
+	5,  // 8: hedge.v1.DebianPackage.replaces:type_name -> hedge.v1.DebianDependency
+	5,  // 9: hedge.v1.DebianPackage.suggests:type_name -> hedge.v1.DebianDependency
+	5,  // 10: hedge.v1.DebianPackage.enhances:type_name -> hedge.v1.DebianDependency
+	5,  // 11: hedge.v1.DebianPackage.breaks:type_name -> hedge.v1.DebianDependency
+	4,  // 12: hedge.v1.DebianPackage.provides:type_name -> hedge.v1.DebianRelation
+	20, // 13: hedge.v1.DebianPackage.extra_fields:type_name -> hedge.v1.DebianPackage.ExtraFieldsEntry
+	2,  // 14: hedge.v1.DebianPackage.files:type_name -> hedge.v1.DebianFile
+	21, // 15: hedge.v1.DebianPackage.maintainer_scripts:type_name -> hedge.v1.DebianPackage.MaintainerScriptsEntry
+	4,  // 16: hedge.v1.DebianDependency.alternatives:type_name -> hedge.v1.DebianRelation
+	1,  // 17: hedge.v1.DebianPackages.packages:type_name -> hedge.v1.DebianPackage
+	32, // 18: hedge.v1.DebianSnapshot.created:type_name -> google.protobuf.Timestamp
+	0,  // 19: hedge.v1.DebianSnapshot.release:type_name -> hedge.v1.DebianRelease
+	22, // 20: hedge.v1.DebianSnapshot.packages:type_name -> hedge.v1.DebianSnapshot.PackagesEntry
+	23, // 21: hedge.v1.DebianSnapshots.snapshots:type_name -> hedge.v1.DebianSnapshots.Entry
+	24, // 22: hedge.v1.DebianPackagesDiffs.patches:type_name -> hedge.v1.DebianPackagesDiffs.Patch
+	25, // 23: hedge.v1.DebianRetainedPackages.entries:type_name -> hedge.v1.DebianRetainedPackages.Entry
+	27, // 24: hedge.v1.DebianSource.files:type_name -> hedge.v1.DebianSource.File
+	26, // 25: hedge.v1.DebianSource.extra_fields:type_name -> hedge.v1.DebianSource.ExtraFieldsEntry
+	11, // 26: hedge.v1.DebianSources.sources:type_name -> hedge.v1.DebianSource
+	28, // 27: hedge.v1.DebianContents.entries:type_name -> hedge.v1.DebianContents.Entry
+	29, // 28: hedge.v1.DebianTranslations.entries:type_name -> hedge.v1.DebianTranslations.Entry
+	32, // 29: hedge.v1.DebianHostedPackages.updated:type_name -> google.protobuf.Timestamp
+	30, // 30: hedge.v1.DebianHostedPackages.packages:type_name -> hedge.v1.DebianHostedPackages.PackagesEntry
+	31, // 31: hedge.v1.DebianRendering.files:type_name -> hedge.v1.DebianRendering.FilesEntry
+	19, // 32: hedge.v1.DebianRelease.DigestsEntry.value:type_name -> hedge.v1.DebianRelease.DigestedFile
+	3,  // 33: hedge.v1.DebianPackage.MaintainerScriptsEntry.value:type_name -> hedge.v1.DebianScript
+	6,  // 34: hedge.v1.DebianSnapshot.PackagesEntry.value:type_name -> hedge.v1.DebianPackages
+	1,  // 35: hedge.v1.DebianRetainedPackages.Entry.package:type_name -> hedge.v1.DebianPackage
+	32, // 36: hedge.v1.DebianRetainedPackages.Entry.removed:type_name -> google.protobuf.Timestamp
+	32, // 37: hedge.v1.DebianRetainedPackages.Entry.pinned_until:type_name -> google.protobuf.Timestamp
+	6,  // 38: hedge.v1.DebianHostedPackages.PackagesEntry.value:type_name -> hedge.v1.DebianPackages
+	19, // 39: hedge.v1.DebianRendering.FilesEntry.value:type_name -> hedge.v1.DebianRelease.DigestedFile
+	40, // [40:40] is the sub-list for method output_type
+	40, // [40:40] is the sub-list for method input_type
+	40, // [40:40] is the sub-list for extension type_name
+	40, // [40:40] is the sub-list for extension extendee
+	0,  // [0:40] is the sub-list for field type_name
+}
+
+func init() { file_hedge_v1_debian_proto_init() }
+func file_hedge_v1_debian_proto_init() {
+	if File_hedge_v1_debian_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_hedge_v1_debian_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianRelease); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianPackage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianFile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianScript); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
 		}
 		file_hedge_v1_debian_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianRelation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianDependency); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianPackages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSnapshot); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSnapshots); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianPackagesDiffs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianRetainedPackages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSources); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianContents); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianTranslations); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianHostedPackages); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianRendering); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DebianRelease_DigestedFile); i {
 			case 0:
 				return &v.state
@@ -802,6 +2625,78 @@ func file_hedge_v1_debian_proto_init() {
 				return nil
 			}
 		}
+		file_hedge_v1_debian_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSnapshots_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianPackagesDiffs_Patch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianRetainedPackages_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianSource_File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianContents_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_hedge_v1_debian_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DebianTranslations_Entry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -809,7 +2704,7 @@ func file_hedge_v1_debian_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_hedge_v1_debian_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   0,
 		},